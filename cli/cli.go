@@ -0,0 +1,272 @@
+// Package cli implements the worm command, which sends the wormholes transactions
+// of the client and runs its queries from the command line:
+//
+//	export WORM_PRIVATE_KEY=7c6786275d6011adb6288587757653d3f9061275bafc2c35ae62efe0bc4973e9
+//	worm -rpc http://127.0.0.1:8545 mint -royalty 10 -meta-url /ipfs/ddfd90be9408b4
+//	worm -rpc http://127.0.0.1:8545 account-info -address 0x0000000000000000000000000000000000000001
+//
+// The results are written to the standard output as JSON, the errors to the
+// standard error as a JSON object with an "error" field.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/wallet"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// KeyEnv is the environment variable holding the hex private key of the sender
+	KeyEnv = "WORM_PRIVATE_KEY"
+	// RPCEnv is the environment variable holding the URL of the node, overridden by -rpc
+	RPCEnv = "WORM_RPC"
+	// DefaultRPC is the URL of the node when neither -rpc nor WORM_RPC is set
+	DefaultRPC = "http://127.0.0.1:8545"
+)
+
+// command is a subcommand of worm. Its flags are registered by setup, which returns
+// the function running the command once the flags are parsed.
+type command struct {
+	name    string
+	help    string
+	tx      bool // the command sends a transaction and needs a key
+	key     bool // the command signs with the key without sending a transaction
+	offline bool // the command does not connect to the node
+	setup   func(fs *flag.FlagSet) runFunc
+}
+
+type runFunc func(ctx context.Context, worm *client.Wormholes) (interface{}, error)
+
+var commands = map[string]*command{}
+
+func register(cmds ...*command) {
+	for _, cmd := range cmds {
+		commands[cmd.name] = cmd
+	}
+}
+
+// options are the global flags of worm
+type options struct {
+	rpc      string
+	keyEnv   string
+	keystore string
+	account  string
+	password string
+	timeout  time.Duration
+	wait     bool
+	confirms uint64
+	dryRun   bool
+}
+
+// Run runs worm with the command line args, without the program name, and returns its exit status
+func Run(args []string, stdout, stderr io.Writer) int {
+	var opts options
+	fs := flag.NewFlagSet("worm", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rpc := os.Getenv(RPCEnv)
+	if rpc == "" {
+		rpc = DefaultRPC
+	}
+	fs.StringVar(&opts.rpc, "rpc", rpc, "URL of the wormholes node, also read from "+RPCEnv)
+	fs.StringVar(&opts.keyEnv, "key-env", KeyEnv, "environment variable holding the hex private key of the sender")
+	fs.StringVar(&opts.keystore, "keystore", "", "keystore directory holding the key of the sender, instead of the environment variable")
+	fs.StringVar(&opts.account, "account", "", "address of the sender in the keystore")
+	fs.StringVar(&opts.password, "password", "", "file holding the password of the keystore account")
+	fs.DurationVar(&opts.timeout, "timeout", time.Minute, "timeout of the command")
+	fs.BoolVar(&opts.wait, "wait", false, "wait until the transaction is mined and print its result")
+	fs.Uint64Var(&opts.confirms, "confirmations", 0, "number of blocks mined on top of the transaction with -wait")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "simulate the transaction with eth_call instead of sending it")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return exitStatus(err)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	cmd, args := lookup(fs.Args())
+	if cmd == nil {
+		fmt.Fprintf(stderr, "worm: unknown command %q\n", strings.Join(args, " "))
+		fs.Usage()
+		return 2
+	}
+
+	cmdFlags := flag.NewFlagSet("worm "+cmd.name, flag.ContinueOnError)
+	cmdFlags.SetOutput(stderr)
+	run := cmd.setup(cmdFlags)
+	if err := cmdFlags.Parse(args); err != nil {
+		return exitStatus(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	result, err := opts.run(ctx, cmd, run)
+	if err != nil {
+		writeJSON(stderr, map[string]string{"error": err.Error()})
+		return 1
+	}
+	writeJSON(stdout, result)
+	return 0
+}
+
+// lookup returns the command named by the first args, such as "sign buyer",
+// and the remaining args. When there is none, it returns the unknown name.
+func lookup(args []string) (*command, []string) {
+	if len(args) > 1 {
+		if cmd, ok := commands[args[0]+" "+args[1]]; ok {
+			return cmd, args[2:]
+		}
+		for name := range commands {
+			if strings.HasPrefix(name, args[0]+" ") {
+				return nil, args[:2]
+			}
+		}
+	}
+	if cmd, ok := commands[args[0]]; ok {
+		return cmd, args[1:]
+	}
+	return nil, args[:1]
+}
+
+// run creates the client of cmd and runs it
+func (opts *options) run(ctx context.Context, cmd *command, run runFunc) (interface{}, error) {
+	var signer client.Signer
+	if cmd.tx || cmd.key {
+		var err error
+		signer, err = opts.signer()
+		if err != nil {
+			return nil, err
+		}
+	}
+	rawurl := opts.rpc
+	if cmd.offline {
+		rawurl = ""
+	}
+	worm, err := client.NewClientWithSigner(signer, rawurl)
+	if err != nil {
+		return nil, xerrors.Errorf("connect to %s: %w", opts.rpc, err)
+	}
+	defer worm.CloseConnect()
+	if !cmd.tx {
+		return run(ctx, worm)
+	}
+
+	worm.SetDryRun(opts.dryRun)
+	result, err := run(ctx, worm)
+	if err != nil {
+		return nil, err
+	}
+	hash, _ := result.(string)
+	switch {
+	case opts.dryRun:
+		return map[string]interface{}{"simulated": true, "from": signer.Address()}, nil
+	case opts.wait:
+		mined, err := worm.WaitMined(ctx, hash, client.WaitOptions{Confirmations: opts.confirms})
+		if err != nil {
+			return nil, err
+		}
+		return newTxOutput(mined), nil
+	}
+	return map[string]string{"hash": hash}, nil
+}
+
+// signer returns the signer of the sender, from the keystore when -keystore is given
+// and from the environment variable otherwise
+func (opts *options) signer() (client.Signer, error) {
+	if opts.keystore != "" {
+		if !common.IsHexAddress(opts.account) {
+			return nil, xerrors.Errorf("-account must be the address of the sender in the keystore, got %q", opts.account)
+		}
+		if opts.password == "" {
+			return nil, xerrors.New("-password must name the file holding the password of the keystore account")
+		}
+		return wallet.NewKeyStore(opts.keystore).Unlock(common.HexToAddress(opts.account), opts.password)
+	}
+	key := os.Getenv(opts.keyEnv)
+	if key == "" {
+		return nil, xerrors.Errorf("no key: set %s or use -keystore", opts.keyEnv)
+	}
+	signer, err := client.NewKeySigner(strings.TrimSpace(key))
+	if err != nil {
+		return nil, xerrors.Errorf("invalid private key in %s: %w", opts.keyEnv, err)
+	}
+	return signer, nil
+}
+
+// txOutput is the JSON output of a mined transaction
+type txOutput struct {
+	Hash          common.Hash `json:"hash"`
+	Status        uint64      `json:"status"`
+	GasUsed       uint64      `json:"gasUsed"`
+	BlockNumber   uint64      `json:"blockNumber"`
+	BlockHash     common.Hash `json:"blockHash"`
+	Confirmations uint64      `json:"confirmations"`
+}
+
+func newTxOutput(r *client.TxResult) *txOutput {
+	return &txOutput{
+		Hash:          r.Hash,
+		Status:        r.Status,
+		GasUsed:       r.GasUsed,
+		BlockNumber:   r.BlockNumber,
+		BlockHash:     r.BlockHash,
+		Confirmations: r.Confirmations,
+	}
+}
+
+// exitStatus returns the exit status of a command line parsing error,
+// asking for the usage with -h is not an error
+func exitStatus(err error) int {
+	if err == flag.ErrHelp {
+		return 0
+	}
+	return 2
+}
+
+func writeJSON(w io.Writer, v interface{}) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func usage(fs *flag.FlagSet) {
+	out := fs.Output()
+	fmt.Fprintf(out, "Usage: worm [flags] <command> [command flags]\n\nFlags:\n")
+	fs.PrintDefaults()
+
+	var txs, orders, queries []string
+	for name, cmd := range commands {
+		switch {
+		case cmd.tx:
+			txs = append(txs, name)
+		case cmd.offline:
+			orders = append(orders, name)
+		default:
+			queries = append(queries, name)
+		}
+	}
+	sort.Strings(txs)
+	sort.Strings(orders)
+	sort.Strings(queries)
+	for _, group := range []struct {
+		title string
+		names []string
+	}{{"Transactions", txs}, {"Orders", orders}, {"Queries", queries}} {
+		fmt.Fprintf(out, "\n%s:\n", group.title)
+		for _, name := range group.names {
+			fmt.Fprintf(out, "  %-40s %s\n", name, commands[name].help)
+		}
+	}
+	fmt.Fprintf(out, "\nRun worm <command> -h for the flags of a command.\n")
+}
@@ -0,0 +1,309 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"github.com/wormholes-org/wormholes-client/types"
+	"github.com/wormholes-org/wormholes-client/unit"
+	"golang.org/x/xerrors"
+)
+
+// The sign commands sign the orders offline with the key of the sender and print them
+// as the trade commands and methods take them. The numbers are given in decimal or hex.
+func init() {
+	register(
+		&command{name: "sign buyer", help: "sign a buyer order", key: true, offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			price := fs.String("price", "", `price offered, in wei or with its unit such as "1.5 ERB"`)
+			nft := fs.String("nft", "", "`address` of the NFT or SNFT, empty for an unminted NFT")
+			exchanger := fs.String("exchanger", "", "`address` of the exchanger")
+			blockNumber := fs.String("block-number", "", "block `number` until which the order is valid")
+			seller := fs.String("seller", "", "`address` of the seller")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				amount, err := hexAmount("price", *price)
+				if err != nil {
+					return nil, err
+				}
+				number, err := hexNumber("block-number", *blockNumber)
+				if err != nil {
+					return nil, err
+				}
+				err = checkAddresses(map[string]string{"seller": *seller}, map[string]string{"exchanger": *exchanger})
+				if err != nil {
+					return nil, err
+				}
+				err = checkNFT(*nft, false)
+				if err != nil {
+					return nil, err
+				}
+				return signed(worm.SignBuyer(amount, *nft, *exchanger, number, *seller))
+			}
+		}},
+		&command{name: "sign seller1", help: "sign a seller order of a minted NFT", key: true, offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			price := fs.String("price", "", `price asked, in wei or with its unit such as "1.5 ERB"`)
+			nft := fs.String("nft", "", "`address` of the NFT or SNFT")
+			exchanger := fs.String("exchanger", "", "`address` of the exchanger")
+			blockNumber := fs.String("block-number", "", "block `number` until which the order is valid")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				amount, err := hexAmount("price", *price)
+				if err != nil {
+					return nil, err
+				}
+				number, err := hexNumber("block-number", *blockNumber)
+				if err != nil {
+					return nil, err
+				}
+				err = checkAddresses(nil, map[string]string{"exchanger": *exchanger})
+				if err != nil {
+					return nil, err
+				}
+				err = checkNFT(*nft, true)
+				if err != nil {
+					return nil, err
+				}
+				return signed(worm.SignSeller1(amount, *nft, *exchanger, number))
+			}
+		}},
+		&command{name: "sign seller2", help: "sign a seller order of an unminted NFT", key: true, offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			price := fs.String("price", "", `price asked, in wei or with its unit such as "1.5 ERB"`)
+			royalty := fs.String("royalty", "", "royalty of the creator")
+			metaURL := fs.String("meta-url", "", "URL of the NFT metadata")
+			exclusive := fs.Bool("exclusive", false, "only the exchanger can trade the NFT")
+			exchanger := fs.String("exchanger", "", "`address` of the exchanger")
+			blockNumber := fs.String("block-number", "", "block `number` until which the order is valid")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				amount, err := hexAmount("price", *price)
+				if err != nil {
+					return nil, err
+				}
+				royalty, err := hexNumber("royalty", *royalty)
+				if err != nil {
+					return nil, err
+				}
+				number, err := hexNumber("block-number", *blockNumber)
+				if err != nil {
+					return nil, err
+				}
+				err = checkAddresses(nil, map[string]string{"exchanger": *exchanger})
+				if err != nil {
+					return nil, err
+				}
+				exclusiveFlag := "0"
+				if *exclusive {
+					exclusiveFlag = "1"
+				}
+				return signed(worm.SignSeller2(amount, royalty, *metaURL, exclusiveFlag, *exchanger, number))
+			}
+		}},
+		&command{name: "sign exchanger", help: "sign an exchanger authorization", key: true, offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			to := fs.String("to", "", "`address` of the authorized exchanger")
+			blockNumber := fs.String("block-number", "", "block `number` until which the authorization is valid")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				number, err := hexNumber("block-number", *blockNumber)
+				if err != nil {
+					return nil, err
+				}
+				err = checkAddresses(nil, map[string]string{"to": *to})
+				if err != nil {
+					return nil, err
+				}
+				signer, err := worm.Signer()
+				if err != nil {
+					return nil, err
+				}
+				return signed(worm.SignExchanger(signer.Address().Hex(), *to, number))
+			}
+		}},
+		&command{name: "sign delegate", help: "sign the delegation to a proxy of account-delegate and token-pledge", key: true, offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			proxyAddress := fs.String("proxy-address", "", "`address` of the proxy")
+			pledgeAccount := fs.String("pledge-account", "", "`address` of the delegated account")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				err := checkAddresses(nil, map[string]string{"proxy-address": *proxyAddress, "pledge-account": *pledgeAccount})
+				if err != nil {
+					return nil, err
+				}
+				sig, err := worm.SignDelegate(*proxyAddress, *pledgeAccount)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{"proxySign": string(sig), "proxyAddress": *proxyAddress}, nil
+			}
+		}},
+		&command{name: "verify", help: "print the signers of orders, checking the expected signers", offline: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			seller1 := orderFlag(fs, "seller1", "seller order of a minted NFT")
+			seller2 := orderFlag(fs, "seller2", "seller order of an unminted NFT")
+			exchangerAuth := orderFlag(fs, "exchanger-auth", "exchanger authorization, checked to be signed by its owner")
+			buyerSigner := fs.String("buyer-signer", "", "expected signer `address` of the buyer order")
+			sellerSigner := fs.String("seller-signer", "", "expected signer `address` of the seller order")
+			exchangerSigner := fs.String("exchanger-signer", "", "expected signer `address` of the exchanger authorization")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return verify(
+					signedOrder{buyer, *buyerSigner, "buyer-signer"},
+					signedOrder{seller1, *sellerSigner, "seller-signer"},
+					signedOrder{seller2, *sellerSigner, "seller-signer"},
+					signedOrder{exchangerAuth, *exchangerSigner, "exchanger-signer"},
+				)
+			}
+		}},
+	)
+}
+
+// signed returns the JSON of an order signed by the wallet
+func signed(order []byte, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(order), nil
+}
+
+// verification is the output of verify for an order
+type verification struct {
+	Order  string         `json:"order"`
+	Signer common.Address `json:"signer"`
+}
+
+// signedOrder is an order checked by verify with its expected signer, any signer
+// when it is empty, and the name of the flag setting it
+type signedOrder struct {
+	*order
+	signer string
+	flag   string
+}
+
+// verify recovers the signers of the orders given and checks them against their
+// expected signers
+func verify(orders ...signedOrder) ([]*verification, error) {
+	var result []*verification
+	for _, o := range orders {
+		if o.value == "" {
+			continue
+		}
+		var signer common.Address
+		if o.signer != "" {
+			if !common.IsHexAddress(o.signer) {
+				return nil, xerrors.Errorf("-%s is not an address: %q", o.flag, o.signer)
+			}
+			signer = common.HexToAddress(o.signer)
+		}
+		data, err := readOrders(o.order)
+		if err != nil {
+			return nil, err
+		}
+		var recovered common.Address
+		switch o.name {
+		case "buyer":
+			var b types.Buyer
+			if err = json.Unmarshal(data[0], &b); err == nil {
+				recovered, err = b.Verify(signer)
+			}
+		case "seller1":
+			var s types.Seller1
+			if err = json.Unmarshal(data[0], &s); err == nil {
+				recovered, err = s.Verify(signer)
+			}
+		case "seller2":
+			var s types.Seller2
+			if err = json.Unmarshal(data[0], &s); err == nil {
+				recovered, err = s.Verify(signer)
+			}
+		case "exchanger-auth":
+			var a types.ExchangerAuth
+			if err = json.Unmarshal(data[0], &a); err == nil {
+				recovered, err = a.Verify()
+			}
+			if err == nil && signer != (common.Address{}) && recovered != signer {
+				err = &types.SignatureMismatchError{Order: "exchanger_auth", Want: signer, Signer: recovered}
+			}
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("-%s: %w", o.name, err)
+		}
+		result = append(result, &verification{Order: o.name, Signer: recovered})
+	}
+	if len(result) == 0 {
+		return nil, xerrors.New("no order to verify: use -buyer, -seller1, -seller2 or -exchanger-auth")
+	}
+	return result, nil
+}
+
+// hexNumber returns the decimal or hex number of the flag name as the hex string of the orders
+func hexNumber(name, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", xerrors.Errorf("-%s is required", name)
+	}
+	n, ok := parseNumber(value)
+	if !ok {
+		return "", xerrors.Errorf("-%s is not a decimal or hex number: %q", name, value)
+	}
+	return hexutil.EncodeBig(n), nil
+}
+
+// hexAmount is like hexNumber but also accepts an amount with its unit, such as "1.5 ERB"
+func hexAmount(name, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if _, ok := parseNumber(value); ok || value == "" {
+		return hexNumber(name, value)
+	}
+	wei, err := unit.Parse(value)
+	if err != nil {
+		return "", xerrors.Errorf("-%s: %w", name, err)
+	}
+	return hexutil.EncodeBig(wei), nil
+}
+
+// parseNumber parses a non negative decimal or hex number
+func parseNumber(value string) (*big.Int, bool) {
+	base := 10
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		value, base = value[2:], 16
+	}
+	n, ok := new(big.Int).SetString(value, base)
+	if !ok || n.Sign() < 0 {
+		return nil, false
+	}
+	return n, true
+}
+
+// checkAddresses checks the addresses of the flags, the optional ones may be empty
+func checkAddresses(optional, required map[string]string) error {
+	for name, address := range required {
+		if address == "" {
+			return xerrors.Errorf("-%s is required", name)
+		}
+	}
+	for _, flags := range []map[string]string{optional, required} {
+		for name, address := range flags {
+			if address != "" && !common.IsHexAddress(address) {
+				return xerrors.Errorf("-%s is not an address: %q", name, address)
+			}
+		}
+	}
+	return nil
+}
+
+// checkNFT checks the -nft flag, the address of an SNFT may be shorter than an address
+func checkNFT(nft string, required bool) error {
+	if nft == "" {
+		if required {
+			return xerrors.New("-nft is required")
+		}
+		return nil
+	}
+	if err := tools.CheckHex("-nft", nft); err != nil {
+		return err
+	}
+	digits := nft[2:]
+	if len(digits) == 0 || len(digits) > 2*common.AddressLength || strings.Trim(digits, "0123456789abcdefABCDEF") != "" {
+		return xerrors.Errorf("-nft is not an address: %q", nft)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"math/big"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/unit"
+)
+
+func init() {
+	register(
+		&command{name: "balance", help: "print the balance of an account", setup: func(fs *flag.FlagSet) runFunc {
+			address := fs.String("address", "", "`address` of the account")
+			block := blockFlag(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				var number *big.Int
+				if *block >= 0 {
+					number = big.NewInt(*block)
+				}
+				wei, err := worm.BalanceAt(ctx, *address, number)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]string{
+					"address": *address,
+					"wei":     wei.String(),
+					"erb":     unit.FormatUnit(wei, unit.ERB),
+				}, nil
+			}
+		}},
+		&command{name: "account-info", help: "print the wormholes account of an address", setup: func(fs *flag.FlagSet) runFunc {
+			address := fs.String("address", "", "`address` of the account or of the NFT")
+			block := blockFlag(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.GetAccountInfo(ctx, *address, *block)
+			}
+		}},
+		&command{name: "validators", help: "print the validators", setup: func(fs *flag.FlagSet) runFunc {
+			block := blockFlag(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.GetValidators(ctx, *block)
+			}
+		}},
+		&command{name: "beneficiaries", help: "print the beneficiaries of the rewards of a block", setup: func(fs *flag.FlagSet) runFunc {
+			block := blockFlag(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.GetBlockBeneficiaryAddressByNumber(ctx, *block)
+			}
+		}},
+		&command{name: "miner-proxy", help: "print the proxies of a miner", setup: func(fs *flag.FlagSet) runFunc {
+			account := fs.String("account", "", "`address` of the miner")
+			block := blockFlag(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				// eth_queryMinerProxy only takes a block number, not a block tag
+				number := *block
+				if number < 0 {
+					head, err := worm.BlockNumber(ctx)
+					if err != nil {
+						return nil, err
+					}
+					number = int64(head)
+				}
+				return worm.QueryMinerProxy(ctx, number, *account)
+			}
+		}},
+		&command{name: "block-number", help: "print the number of the latest block", setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				number, err := worm.BlockNumber(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]uint64{"blockNumber": number}, nil
+			}
+		}},
+		&command{name: "receipt", help: "print the receipt of a transaction", setup: func(fs *flag.FlagSet) runFunc {
+			hash := fs.String("hash", "", "`hash` of the transaction")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.TransactionReceipt(ctx, *hash)
+			}
+		}},
+	)
+}
+
+// blockFlag registers the -block flag, the latest block when it is negative
+func blockFlag(fs *flag.FlagSet) *int64 {
+	return fs.Int64("block", -1, "block `number`, the latest block if negative")
+}
@@ -0,0 +1,330 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/unit"
+	"golang.org/x/xerrors"
+)
+
+func init() {
+	register(
+		&command{name: "normal-transaction", help: "send ERB to an account", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			to := fs.String("to", "", "recipient `address`")
+			value := amountFlag(fs, "value", "amount sent")
+			data := fs.String("data", "", "data of the transaction")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				wei, err := value.wei()
+				if err != nil {
+					return nil, err
+				}
+				return worm.NormalTransactionWeiContext(ctx, *to, wei, *data)
+			}
+		}},
+		&command{name: "mint", help: "mint an NFT", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			royalty := fs.Uint("royalty", 0, "royalty of the creator")
+			metaURL := fs.String("meta-url", "", "URL of the NFT metadata")
+			exchanger := fs.String("exchanger", "", "`address` of the exchanger of the NFT")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				royalty, err := uint32Flag("royalty", *royalty)
+				if err != nil {
+					return nil, err
+				}
+				return worm.MintContext(ctx, royalty, *metaURL, *exchanger)
+			}
+		}},
+		nftCommand("transfer", "transfer an NFT", (*client.Wormholes).TransferContext),
+		nftCommand("author", "authorize an account to transfer an NFT", (*client.Wormholes).AuthorContext),
+		nftCommand("author-revoke", "revoke the authorization of an NFT", (*client.Wormholes).AuthorRevokeContext),
+		toCommand("account-author", "authorize an account to transfer all the NFTs of the sender", (*client.Wormholes).AccountAuthorContext),
+		toCommand("account-author-revoke", "revoke the authorization of account-author", (*client.Wormholes).AccountAuthorRevokeContext),
+		snftCommand("snft-to-erb", "exchange an SNFT for ERB", (*client.Wormholes).SNFTToERBContext),
+		snftCommand("snft-pledge", "pledge an SNFT", (*client.Wormholes).SNFTPledgeContext),
+		snftCommand("snft-revokes-pledge", "revoke the pledge of an SNFT", (*client.Wormholes).SNFTRevokesPledgeContext),
+		&command{name: "token-pledge", help: "pledge ERB to become a validator", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			proxySign := fs.String("proxy-sign", "", "signature of the proxy")
+			proxyAddress := fs.String("proxy-address", "", "`address` of the proxy")
+			value := amountFlag(fs, "value", "amount pledged")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				wei, err := value.wei()
+				if err != nil {
+					return nil, err
+				}
+				return worm.TokenPledgeWeiContext(ctx, []byte(*proxySign), *proxyAddress, wei)
+			}
+		}},
+		amountCommand("token-revokes-pledge", "revoke a pledge of ERB", "amount revoked", (*client.Wormholes).TokenRevokesPledgeWeiContext),
+		&command{name: "open", help: "open an exchange", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			feeRate := fs.Uint("fee-rate", 0, "fee rate of the exchange")
+			name := fs.String("name", "", "name of the exchange")
+			url := fs.String("url", "", "URL of the exchange server")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				feeRate, err := uint32Flag("fee-rate", *feeRate)
+				if err != nil {
+					return nil, err
+				}
+				return worm.OpenContext(ctx, feeRate, *name, *url)
+			}
+		}},
+		&command{name: "close", help: "close the exchange of the sender", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.CloseContext(ctx)
+			}
+		}},
+		&command{name: "transaction-nft", help: "sell a minted NFT to a buyer", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			to := fs.String("to", "", "`address` of the buyer")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(buyer)
+				if err != nil {
+					return nil, err
+				}
+				return worm.TransactionNFTContext(ctx, orders[0], *to)
+			}
+		}},
+		&command{name: "buyer-initiating-transaction", help: "buy a minted NFT from a seller order", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			seller1 := orderFlag(fs, "seller1", "seller order of a minted NFT")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(seller1)
+				if err != nil {
+					return nil, err
+				}
+				return worm.BuyerInitiatingTransactionContext(ctx, orders[0])
+			}
+		}},
+		&command{name: "foundry-trade-buyer", help: "buy an unminted NFT from a seller order", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			seller2 := orderFlag(fs, "seller2", "seller order of an unminted NFT")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(seller2)
+				if err != nil {
+					return nil, err
+				}
+				return worm.FoundryTradeBuyerContext(ctx, orders[0])
+			}
+		}},
+		&command{name: "foundry-exchange", help: "match a buyer order with an unminted NFT seller order", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			seller2 := orderFlag(fs, "seller2", "seller order of an unminted NFT")
+			to := fs.String("to", "", "`address` of the buyer")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(buyer, seller2)
+				if err != nil {
+					return nil, err
+				}
+				return worm.FoundryExchangeContext(ctx, orders[0], orders[1], *to)
+			}
+		}},
+		&command{name: "nft-exchange-match", help: "match a buyer order with a seller order for an authorized exchanger", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			seller := orderFlag(fs, "seller", "seller order")
+			exchangerAuth := orderFlag(fs, "exchanger-auth", "exchanger authorization")
+			to := fs.String("to", "", "`address` of the buyer")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(buyer, seller, exchangerAuth)
+				if err != nil {
+					return nil, err
+				}
+				return worm.NftExchangeMatchContext(ctx, orders[0], orders[1], orders[2], *to)
+			}
+		}},
+		&command{name: "foundry-exchange-initiated", help: "match a buyer order with an unminted NFT seller order for an authorized exchanger", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			seller2 := orderFlag(fs, "seller2", "seller order of an unminted NFT")
+			exchangerAuth := orderFlag(fs, "exchanger-auth", "exchanger authorization")
+			to := fs.String("to", "", "`address` of the buyer")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(buyer, seller2, exchangerAuth)
+				if err != nil {
+					return nil, err
+				}
+				return worm.FoundryExchangeInitiatedContext(ctx, orders[0], orders[1], orders[2], *to)
+			}
+		}},
+		&command{name: "nft-does-not-authorize-exchanges", help: "match a buyer order with a minted NFT seller order", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			buyer := orderFlag(fs, "buyer", "buyer order")
+			seller1 := orderFlag(fs, "seller1", "seller order of a minted NFT")
+			to := fs.String("to", "", "`address` of the buyer")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				orders, err := readOrders(buyer, seller1)
+				if err != nil {
+					return nil, err
+				}
+				return worm.NFTDoesNotAuthorizeExchangesContext(ctx, orders[0], orders[1], *to)
+			}
+		}},
+		amountCommand("additional-pledge-amount", "add ERB to the pledge of the exchange", "amount added", (*client.Wormholes).AdditionalPledgeAmountWeiContext),
+		amountCommand("revokes-pledge-amount", "revoke ERB from the pledge of the exchange", "amount revoked", (*client.Wormholes).RevokesPledgeAmountWeiContext),
+		&command{name: "vote-official-nft", help: "inject SNFT fragments, for the official accounts", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			vote := voteFlags(fs)
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				royalty, err := uint32Flag("royalty", *vote.royalty)
+				if err != nil {
+					return nil, err
+				}
+				return worm.VoteOfficialNFTContext(ctx, *vote.dir, *vote.startIndex, *vote.number, royalty, *vote.creator)
+			}
+		}},
+		&command{name: "vote-official-nft-by-approved-exchanger", help: "inject SNFT fragments with an exchanger authorization", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			vote := voteFlags(fs)
+			exchangerAuth := orderFlag(fs, "exchanger-auth", "exchanger authorization")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				royalty, err := uint32Flag("royalty", *vote.royalty)
+				if err != nil {
+					return nil, err
+				}
+				orders, err := readOrders(exchangerAuth)
+				if err != nil {
+					return nil, err
+				}
+				return worm.VoteOfficialNFTByApprovedExchangerContext(ctx, *vote.dir, *vote.startIndex, *vote.number, royalty, *vote.creator, orders[0])
+			}
+		}},
+		&command{name: "unforzen-account", help: "change the revenue model of the sender", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.UnforzenAccountContext(ctx)
+			}
+		}},
+		&command{name: "account-delegate", help: "delegate the sender to a proxy account", tx: true, setup: func(fs *flag.FlagSet) runFunc {
+			proxySign := fs.String("proxy-sign", "", "signature of the proxy")
+			proxyAddress := fs.String("proxy-address", "", "`address` of the proxy")
+			return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+				return worm.AccountDelegateContext(ctx, []byte(*proxySign), *proxyAddress)
+			}
+		}},
+	)
+}
+
+// nftCommand is a command taking an NFT and an account
+func nftCommand(name, help string, send func(worm *client.Wormholes, ctx context.Context, nftAddress, to string) (string, error)) *command {
+	return &command{name: name, help: help, tx: true, setup: func(fs *flag.FlagSet) runFunc {
+		nft := fs.String("nft", "", "`address` of the NFT")
+		to := fs.String("to", "", "`address` of the account")
+		return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+			return send(worm, ctx, *nft, *to)
+		}
+	}}
+}
+
+// toCommand is a command taking an account
+func toCommand(name, help string, send func(worm *client.Wormholes, ctx context.Context, to string) (string, error)) *command {
+	return &command{name: name, help: help, tx: true, setup: func(fs *flag.FlagSet) runFunc {
+		to := fs.String("to", "", "`address` of the account")
+		return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+			return send(worm, ctx, *to)
+		}
+	}}
+}
+
+// snftCommand is a command taking an SNFT
+func snftCommand(name, help string, send func(worm *client.Wormholes, ctx context.Context, snftAddress string) (string, error)) *command {
+	return &command{name: name, help: help, tx: true, setup: func(fs *flag.FlagSet) runFunc {
+		snft := fs.String("snft", "", "`address` of the SNFT")
+		return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+			return send(worm, ctx, *snft)
+		}
+	}}
+}
+
+// amountCommand is a command taking an amount of ERB
+func amountCommand(name, help, usage string, send func(worm *client.Wormholes, ctx context.Context, value *big.Int) (string, error)) *command {
+	return &command{name: name, help: help, tx: true, setup: func(fs *flag.FlagSet) runFunc {
+		value := amountFlag(fs, "value", usage)
+		return func(ctx context.Context, worm *client.Wormholes) (interface{}, error) {
+			wei, err := value.wei()
+			if err != nil {
+				return nil, err
+			}
+			return send(worm, ctx, wei)
+		}
+	}}
+}
+
+// amount is the value of a flag holding an amount with its denomination
+type amount struct {
+	name  string
+	value string
+}
+
+func amountFlag(fs *flag.FlagSet, name, usage string) *amount {
+	a := &amount{name: name}
+	fs.StringVar(&a.value, name, "", usage+`, with its unit such as "1.5 ERB", "30 gwei" or "1000 wei"`)
+	return a
+}
+
+func (a *amount) wei() (*big.Int, error) {
+	if a.value == "" {
+		return nil, xerrors.Errorf("-%s is required", a.name)
+	}
+	wei, err := unit.Parse(a.value)
+	if err != nil {
+		return nil, xerrors.Errorf("-%s: %w", a.name, err)
+	}
+	return wei, nil
+}
+
+// order is the value of a flag holding a JSON order, or the name of the file
+// holding it prefixed with @
+type order struct {
+	name  string
+	value string
+}
+
+func orderFlag(fs *flag.FlagSet, name, usage string) *order {
+	o := &order{name: name}
+	fs.StringVar(&o.value, name, "", usage+" as JSON, or @file to read it from file")
+	return o
+}
+
+// readOrders returns the JSON of the orders
+func readOrders(orders ...*order) ([][]byte, error) {
+	data := make([][]byte, len(orders))
+	for i, o := range orders {
+		value := strings.TrimSpace(o.value)
+		if value == "" {
+			return nil, xerrors.Errorf("-%s is required", o.name)
+		}
+		if !strings.HasPrefix(value, "@") {
+			data[i] = []byte(value)
+			continue
+		}
+		b, err := ioutil.ReadFile(value[1:])
+		if err != nil {
+			return nil, xerrors.Errorf("-%s: %w", o.name, err)
+		}
+		data[i] = []byte(strings.TrimSpace(string(b)))
+	}
+	return data, nil
+}
+
+// vote are the flags of the SNFT fragments injection
+type vote struct {
+	dir        *string
+	startIndex *string
+	number     *uint64
+	royalty    *uint
+	creator    *string
+}
+
+func voteFlags(fs *flag.FlagSet) *vote {
+	return &vote{
+		dir:        fs.String("dir", "", "path of the fragments"),
+		startIndex: fs.String("start-index", "", "hex start number of the fragments"),
+		number:     fs.Uint64("number", 0, "number of fragments injected"),
+		royalty:    fs.Uint("royalty", 0, "royalty of the creator"),
+		creator:    fs.String("creator", "", "`address` of the creator"),
+	}
+}
+
+// uint32Flag checks that the value of the flag name fits an uint32, such as a royalty or a fee rate
+func uint32Flag(name string, value uint) (uint32, error) {
+	if uint64(value) > math.MaxUint32 {
+		return 0, xerrors.Errorf("-%s is out of range: %d", name, value)
+	}
+	return uint32(value), nil
+}
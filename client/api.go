@@ -1,5 +1,7 @@
 package client
 
+import "context"
+
 type APIs interface {
 	NormalTransaction(to string, value int64, data string) (string, error)
 	Mint(royalty uint32, metaURL string, exchanger string) (string, error)
@@ -29,3 +31,35 @@ type APIs interface {
 	UnforzenAccount() (string, error)                                                                                                               //25
 	AccountDelegate(proxySign []byte, proxyAddress string) (string, error)                                                                          //31
 }
+
+// ContextAPIs are the APIs methods taking a context, which is used for every request sent to the node
+// and allows to cancel a transaction or to set a deadline on it.
+type ContextAPIs interface {
+	NormalTransactionContext(ctx context.Context, to string, value int64, data string) (string, error)
+	MintContext(ctx context.Context, royalty uint32, metaURL string, exchanger string) (string, error)
+	TransferContext(ctx context.Context, wormAddress, to string) (string, error)
+	AuthorContext(ctx context.Context, wormAddress, to string) (string, error)
+	AuthorRevokeContext(ctx context.Context, wormAddress, to string) (string, error)
+	AccountAuthorContext(ctx context.Context, to string) (string, error)
+	AccountAuthorRevokeContext(ctx context.Context, to string) (string, error)
+	SNFTToERBContext(ctx context.Context, wormAddress string) (string, error)
+	SNFTPledgeContext(ctx context.Context, snftAddress string) (string, error)
+	SNFTRevokesPledgeContext(ctx context.Context, snftAddress string) (string, error)
+	TokenPledgeContext(ctx context.Context, proxySign []byte, proxyAddress string, value int64) (string, error)
+	TokenRevokesPledgeContext(ctx context.Context, value int64) (string, error)
+	OpenContext(ctx context.Context, feeRate uint32, name, url string) (string, error)
+	CloseContext(ctx context.Context) (string, error)
+	TransactionNFTContext(ctx context.Context, buyer []byte, to string) (string, error)
+	BuyerInitiatingTransactionContext(ctx context.Context, seller1 []byte) (string, error)
+	FoundryTradeBuyerContext(ctx context.Context, seller2 []byte) (string, error)
+	FoundryExchangeContext(ctx context.Context, buyer, seller2 []byte, to string) (string, error)
+	NftExchangeMatchContext(ctx context.Context, buyer, seller, exchangerAuth []byte, to string) (string, error)
+	FoundryExchangeInitiatedContext(ctx context.Context, buyer, seller2, exchangerAuth []byte, to string) (string, error)
+	NFTDoesNotAuthorizeExchangesContext(ctx context.Context, buyer, seller1 []byte, to string) (string, error)
+	AdditionalPledgeAmountContext(ctx context.Context, value int64) (string, error)
+	RevokesPledgeAmountContext(ctx context.Context, value int64) (string, error)
+	VoteOfficialNFTContext(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string) (string, error)
+	VoteOfficialNFTByApprovedExchangerContext(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error)
+	UnforzenAccountContext(ctx context.Context) (string, error)
+	AccountDelegateContext(ctx context.Context, proxySign []byte, proxyAddress string) (string, error)
+}
@@ -0,0 +1,252 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
+)
+
+// BatchOp builds one transaction of a batch, usually with one of the TxBuilder methods
+//
+//	func(b *client.TxBuilder) (*types.Transaction, error) {
+//		return b.Transfer(nftAddress, to)
+//	}
+type BatchOp func(b *TxBuilder) (*types.Transaction, error)
+
+// BatchResult is the outcome of one transaction of a batch
+type BatchResult struct {
+	Hash string
+	Err  error
+}
+
+// SendBatch builds and signs the transactions of ops with consecutive nonces and sends
+// them to the node in a single JSON-RPC batch request. The result of each transaction is
+// returned at its index; the error is only set when the batch could not be sent at all.
+//
+// Before the transactions are signed, their gas is estimated and the head of the chain is
+// read for the expiry check of their orders, in one more batch request. A transaction
+// whose gas can not be estimated, for instance because it depends on an earlier one of
+// the batch, uses the default gas limit of its type.
+//
+// When the node rejects a transaction, the following ones of the batch wait for its nonce
+// in the queue of the node: they keep their hash and their error matches ErrNonceGap.
+// The next transaction sent by the client takes the missing nonce and releases them.
+func (worm *Wormholes) SendBatch(ctx context.Context, ops ...BatchOp) ([]BatchResult, error) {
+	signer, err := worm.Signer()
+	if err != nil {
+		return nil, invalid("SendBatch", err)
+	}
+	account := signer.Address()
+	if worm.c == nil {
+		return nil, rpcError("SendBatch", worm.connError())
+	}
+	builder, err := worm.newTxBuilder(ctx, account)
+	if err != nil {
+		return nil, opError("SendBatch", nil, err)
+	}
+
+	results := make([]BatchResult, len(ops))
+	var (
+		indexes []int
+		calls   []*txCall
+	)
+	for i, op := range ops {
+		c, err := worm.batchCall(builder, op)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		indexes = append(indexes, i)
+		calls = append(calls, c)
+	}
+	if len(calls) == 0 {
+		return results, nil
+	}
+	indexes, calls, err = worm.prepareBatch(ctx, account, results, indexes, calls)
+	if err != nil {
+		worm.logger.Error("failed to prepare the batch", "size", len(calls), "err", err)
+		return nil, err
+	}
+
+	var (
+		elems []rpc.BatchElem
+		sent  []int
+	)
+	for j, c := range calls {
+		elem, hash, err := worm.batchElem(ctx, builder, c)
+		if err != nil {
+			results[indexes[j]].Err = err
+			continue
+		}
+		results[indexes[j]].Hash = hash
+		elems = append(elems, elem)
+		sent = append(sent, j)
+	}
+	if len(elems) == 0 {
+		return results, nil
+	}
+
+	err = worm.c.BatchCallContext(ctx, elems)
+	if err != nil {
+		worm.nonces.reset(account)
+		worm.logger.Error("failed to send the batch", "size", len(elems), "err", err)
+		return nil, rpcError("SendBatch", err)
+	}
+	rejected := -1
+	for k, elem := range elems {
+		i, c := indexes[sent[k]], calls[sent[k]]
+		switch {
+		case elem.Error != nil:
+			results[i] = BatchResult{Err: sendError(c.name, elem.Error, c.payload != nil)}
+			worm.logger.Error("batch transaction rejected", "op", c.name, "index", i, "err", elem.Error)
+			if !worm.dryRun && rejected < 0 {
+				rejected = i
+				worm.nonces.reset(account)
+			}
+		case rejected >= 0:
+			results[i].Err = opError(c.name, ErrNonceGap, xerrors.Errorf("the transaction %d of the batch was rejected, this one waits for its nonce", rejected))
+			worm.logger.Warn("batch transaction waits for a missing nonce", "op", c.name, "index", i, "hash", results[i].Hash)
+		}
+	}
+	worm.logger.Info("batch sent", "size", len(elems), "from", account, "dryRun", worm.dryRun)
+	return results, nil
+}
+
+// prepareBatch estimates the gas of calls sent from the account from and checks the
+// expiry of their orders against the head of the chain, in one batch request. It returns
+// the calls which passed the checks with their indexes, the errors of the others are set
+// in results.
+func (worm *Wormholes) prepareBatch(ctx context.Context, from common.Address, results []BatchResult, indexes []int, calls []*txCall) ([]int, []*txCall, error) {
+	gas := make([]hexutil.Uint64, len(calls))
+	elems := make([]rpc.BatchElem, len(calls), len(calls)+1)
+	for j, c := range calls {
+		elems[j] = rpc.BatchElem{
+			Method: "eth_estimateGas",
+			Args:   []interface{}{toCallArg(estimateMsg(from, c))},
+			Result: &gas[j],
+		}
+	}
+	checkExpiry := false
+	for _, c := range calls {
+		if worm.expiryCheck && hasOrders(c.payload) {
+			checkExpiry = true
+		}
+	}
+	var head hexutil.Uint64
+	if checkExpiry {
+		elems = append(elems, rpc.BatchElem{Method: "eth_blockNumber", Result: &head})
+	}
+	err := worm.c.BatchCallContext(ctx, elems)
+	if err != nil {
+		return nil, nil, rpcError("SendBatch", err)
+	}
+	if checkExpiry && elems[len(calls)].Error != nil {
+		return nil, nil, rpcError("SendBatch", elems[len(calls)].Error)
+	}
+
+	var (
+		kept      []int
+		keptCalls []*txCall
+	)
+	for j, c := range calls {
+		c.gasLimit = worm.gasLimit(c, uint64(gas[j]), elems[j].Error)
+		if checkExpiry {
+			err := worm.checkOrdersExpiry(c, uint64(head))
+			if err != nil {
+				worm.logger.Warn("order expiry check failed", "op", c.name, "err", err)
+				results[indexes[j]].Err = err
+				continue
+			}
+		}
+		kept = append(kept, indexes[j])
+		keptCalls = append(keptCalls, c)
+	}
+	return kept, keptCalls, nil
+}
+
+// batchElem returns the request sending the transaction described by c with the next
+// nonce of the account of builder and its hash, or simulating it in dry run mode
+func (worm *Wormholes) batchElem(ctx context.Context, builder *TxBuilder, c *txCall) (rpc.BatchElem, string, error) {
+	if worm.dryRun {
+		return rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{toCallArg(builder.callMsg(c)), toBlockNumArg(big.NewInt(-1))},
+			Result: new(hexutil.Bytes),
+		}, "", nil
+	}
+
+	nonce, err := worm.nonces.next(ctx, builder.From, worm.PendingNonceAt)
+	if err != nil {
+		return rpc.BatchElem{}, "", opError("SendBatch", nil, err)
+	}
+	b := *builder
+	b.Nonce = nonce
+	signedTx, err := worm.batchTx(&b, c)
+	if err != nil {
+		worm.nonces.release(builder.From, nonce)
+		return rpc.BatchElem{}, "", err
+	}
+	rawTx, err := signedTx.MarshalBinary()
+	if err != nil {
+		worm.nonces.release(builder.From, nonce)
+		return rpc.BatchElem{}, "", opError(c.name, nil, err)
+	}
+	return rpc.BatchElem{
+		Method: "eth_sendRawTransaction",
+		Args:   []interface{}{hexutil.Encode(rawTx)},
+		Result: new(string),
+	}, strings.ToLower(signedTx.Hash().String()), nil
+}
+
+// batchTx builds the transaction described by c with the nonce and the fees of builder
+// and signs it
+func (worm *Wormholes) batchTx(builder *TxBuilder, c *txCall) (*types.Transaction, error) {
+	tx, err := builder.build(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := worm.SignTx(tx, builder.ChainID)
+	if err != nil {
+		return nil, opError(c.name, nil, err)
+	}
+	return signedTx, nil
+}
+
+// batchCall builds the transaction of op, describes it and runs the checks done before
+// sending a single transaction, but the expiry check done for the whole batch
+func (worm *Wormholes) batchCall(builder *TxBuilder, op BatchOp) (*txCall, error) {
+	b := *builder
+	tx, err := op(&b)
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() == nil {
+		return nil, invalid("SendBatch", xerrors.New("the transaction has no recipient"))
+	}
+	c := &txCall{
+		name:     "NormalTransaction",
+		to:       tx.To(),
+		value:    tx.Value(),
+		data:     tx.Data(),
+		gasLimit: tx.Gas(),
+	}
+	payload, err := DecodeData(tx.Data())
+	if err == nil {
+		c.name, _ = types2.TypeName(payload.Type)
+		c.payload = payload
+	}
+	if worm.verifyOrders {
+		err = verifyOrders(c, builder.From)
+		if err != nil {
+			return nil, invalid(c.name, err)
+		}
+	}
+	return c, nil
+}
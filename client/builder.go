@@ -0,0 +1,691 @@
+package client
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/tools"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"github.com/wormholes-org/wormholes-client/unit"
+	"golang.org/x/xerrors"
+)
+
+// txCall describes a wormholes transaction before the nonce, the gas price
+// and the signature are applied.
+type txCall struct {
+	name     string
+	to       *common.Address // nil means the transaction is sent to the sender itself
+	value    *big.Int
+	data     []byte
+	gasLimit uint64
+
+	payload *types2.Transaction // the wormholes transaction carried in data, nil for plain transfers
+}
+
+// recipient returns the receiver of the transaction sent from the account from
+func (c *txCall) recipient(from common.Address) common.Address {
+	if c.to == nil {
+		return from
+	}
+	return *c.to
+}
+
+// erb converts an amount of whole ERB to wei
+func erb(value int64) *big.Int {
+	return unit.ToWei(value, unit.ERB)
+}
+
+// checkWei checks an amount of wei passed to the operation name
+func checkWei(name string, value *big.Int) error {
+	if value == nil || value.Sign() < 0 {
+		return xerrors.Errorf("%s() value must be a non negative amount of wei", name)
+	}
+	return nil
+}
+
+// wormholesPrefix marks the data of an ethereum transaction as a wormholes transaction
+const wormholesPrefix = "wormholes:"
+
+// wormholesData formats the wormholes transaction as the data of an ethereum transaction
+func wormholesData(transaction types2.Transaction) ([]byte, error) {
+	transaction.Version = types2.WormHolesVersion
+	data, err := json.Marshal(transaction)
+	if err != nil {
+		return nil, xerrors.New("failed to format wormholes data")
+	}
+	return append([]byte(wormholesPrefix), data...), nil
+}
+
+func wormholesCall(name string, to *common.Address, value *big.Int, gasLimit uint64, transaction types2.Transaction) (*txCall, error) {
+	err := types2.MatchTerms(&transaction)
+	if err != nil {
+		return nil, err
+	}
+	data, err := wormholesData(transaction)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	return &txCall{
+		name:     name,
+		to:       to,
+		value:    value,
+		data:     data,
+		gasLimit: gasLimit,
+		payload:  &transaction,
+	}, nil
+}
+
+func addressOf(to string) *common.Address {
+	addr := common.HexToAddress(to)
+	return &addr
+}
+
+func parseBuyer(buyer []byte) (*types2.Buyer, error) {
+	var buyers types2.Buyer
+	err := json.Unmarshal(buyer, &buyers)
+	if err != nil {
+		return nil, xerrors.New("the formate of buyer is wrong")
+	}
+	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &buyers, nil
+}
+
+func parseSeller1(seller1 []byte) (*types2.Seller1, error) {
+	var seller1s types2.Seller1
+	err := json.Unmarshal(seller1, &seller1s)
+	if err != nil {
+		return nil, xerrors.New("the formate of seller1 is wrong")
+	}
+	err = tools.CheckHex("seller1s.BlockNumber", seller1s.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &seller1s, nil
+}
+
+func parseSeller2(seller2 []byte) (*types2.Seller2, error) {
+	var seller2s types2.Seller2
+	err := json.Unmarshal(seller2, &seller2s)
+	if err != nil {
+		return nil, xerrors.New("the formate of seller2 is wrong")
+	}
+	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
+	if err != nil {
+		return nil, err
+	}
+	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &seller2s, nil
+}
+
+func parseExchangerAuth(exchangerAuth []byte) (*types2.ExchangerAuth, error) {
+	var exchangerAuths types2.ExchangerAuth
+	err := json.Unmarshal(exchangerAuth, &exchangerAuths)
+	if err != nil {
+		return nil, xerrors.New("the formate of exchangerAuth is wrong")
+	}
+	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangerAuths.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return &exchangerAuths, nil
+}
+
+// amountOf decodes the hex price of an order, the prices are checked by types.MatchTerms
+func amountOf(amount string) *big.Int {
+	value, err := types2.ParseHexBig(amount)
+	if err != nil {
+		return new(big.Int)
+	}
+	return value
+}
+
+func normalTransactionCall(to string, value int64, data string) (*txCall, error) {
+	return normalTransactionWeiCall(to, erb(value), data)
+}
+
+func normalTransactionWeiCall(to string, value *big.Int, data string) (*txCall, error) {
+	err := checkWei("NormalTransaction", value)
+	if err != nil {
+		return nil, err
+	}
+	return &txCall{
+		name:     "NormalTransaction",
+		to:       addressOf(to),
+		value:    value,
+		data:     []byte(data),
+		gasLimit: 51000,
+	}, nil
+}
+
+func mintCall(royalty uint32, metaURL string, exchanger string) (*txCall, error) {
+	if exchanger != "" {
+		err := tools.CheckAddress("Mint() exchanger", exchanger)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return wormholesCall("Mint", nil, nil, 60000, types2.Transaction{
+		Type:      types2.Mint,
+		Royalty:   royalty,
+		MetaURL:   metaURL,
+		Exchanger: exchanger,
+	})
+}
+
+// nftToCall builds the transactions that send an NFT related operation to another account
+func nftToCall(name string, txType uint8, wormAddress, to string) (*txCall, error) {
+	err := tools.CheckHex(name+"() wormAddress", wormAddress)
+	if err != nil {
+		return nil, err
+	}
+	err = tools.CheckAddress(name+"() to", to)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall(name, addressOf(to), nil, 50000, types2.Transaction{
+		Type:       txType,
+		NFTAddress: wormAddress,
+	})
+}
+
+func transferCall(wormAddress, to string) (*txCall, error) {
+	return nftToCall("Transfer", types2.Transfer, wormAddress, to)
+}
+
+func authorCall(wormAddress, to string) (*txCall, error) {
+	return nftToCall("Author", types2.Author, wormAddress, to)
+}
+
+func authorRevokeCall(wormAddress, to string) (*txCall, error) {
+	return nftToCall("AuthorRevoke", types2.AuthorRevoke, wormAddress, to)
+}
+
+func accountAuthorCall(to string) (*txCall, error) {
+	err := tools.CheckAddress("AccountAuthor() to", to)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("AccountAuthor", addressOf(to), nil, 50000, types2.Transaction{
+		Type: types2.AccountAuthor,
+	})
+}
+
+func accountAuthorRevokeCall(to string) (*txCall, error) {
+	err := tools.CheckAddress("AccountAuthorRevoke() to", to)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("AccountAuthorRevoke", addressOf(to), nil, 50000, types2.Transaction{
+		Type: types2.AccountAuthorRevoke,
+	})
+}
+
+func snftToERBCall(wormAddress string) (*txCall, error) {
+	err := tools.CheckHex("SNFTToERB() wormAddress", wormAddress)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("SNFTToERB", nil, nil, 50000, types2.Transaction{
+		Type:       types2.SNFTToERB,
+		NFTAddress: wormAddress,
+	})
+}
+
+func snftPledgeCall(snftAddress string) (*txCall, error) {
+	return wormholesCall("SNFTPledge", nil, erb(100000), 70000, types2.Transaction{
+		Type:       types2.SNFTPledge,
+		NFTAddress: snftAddress,
+	})
+}
+
+func snftRevokesPledgeCall(snftAddress string) (*txCall, error) {
+	return wormholesCall("SNFTRevokesPledge", nil, erb(100000), 50000, types2.Transaction{
+		Type:       types2.SNFTRevokesPledge,
+		NFTAddress: snftAddress,
+	})
+}
+
+func tokenPledgeCall(proxySign []byte, proxyAddress string, value int64) (*txCall, error) {
+	return tokenPledgeWeiCall(proxySign, proxyAddress, erb(value))
+}
+
+func tokenPledgeWeiCall(proxySign []byte, proxyAddress string, value *big.Int) (*txCall, error) {
+	err := checkWei("TokenPledge", value)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("TokenPledge", nil, value, 70000, types2.Transaction{
+		Type:         types2.TokenPledge,
+		ProxyAddress: proxyAddress,
+		ProxySign:    string(proxySign),
+	})
+}
+
+func tokenRevokesPledgeCall(value int64) (*txCall, error) {
+	return tokenRevokesPledgeWeiCall(erb(value))
+}
+
+func tokenRevokesPledgeWeiCall(value *big.Int) (*txCall, error) {
+	err := checkWei("TokenRevokesPledge", value)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("TokenRevokesPledge", nil, value, 50000, types2.Transaction{
+		Type: types2.TokenRevokesPledge,
+	})
+}
+
+func openCall(feeRate uint32, name, url string) (*txCall, error) {
+	return wormholesCall("Open", nil, erb(100), 60000, types2.Transaction{
+		Type:    types2.Open,
+		FeeRate: feeRate,
+		Name:    name,
+		Url:     url,
+	})
+}
+
+func closeCall() (*txCall, error) {
+	return wormholesCall("Close", nil, nil, 60000, types2.Transaction{
+		Type: types2.Close,
+	})
+}
+
+func transactionNFTCall(buyer []byte, to string) (*txCall, error) {
+	err := tools.CheckAddress("TransactionNFT() to", to)
+	if err != nil {
+		return nil, err
+	}
+	buyers, err := parseBuyer(buyer)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("TransactionNFT", addressOf(to), amountOf(buyers.Amount), 100000, types2.Transaction{
+		Type:  types2.TransactionNFT,
+		Buyer: buyers,
+	})
+}
+
+func buyerInitiatingTransactionCall(seller1 []byte) (*txCall, error) {
+	seller1s, err := parseSeller1(seller1)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("BuyerInitiatingTransaction", nil, amountOf(seller1s.Amount), 100000, types2.Transaction{
+		Type:    types2.BuyerInitiatingTransaction,
+		Seller1: seller1s,
+	})
+}
+
+func foundryTradeBuyerCall(seller2 []byte) (*txCall, error) {
+	seller2s, err := parseSeller2(seller2)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("FoundryTradeBuyer", nil, amountOf(seller2s.Amount), 101000, types2.Transaction{
+		Type:    types2.FoundryTradeBuyer,
+		Seller2: seller2s,
+	})
+}
+
+func foundryExchangeCall(buyer, seller2 []byte, to string) (*txCall, error) {
+	err := tools.CheckAddress("FoundryExchange() to", to)
+	if err != nil {
+		return nil, err
+	}
+	buyers, err := parseBuyer(buyer)
+	if err != nil {
+		return nil, err
+	}
+	seller2s, err := parseSeller2(seller2)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("FoundryExchange", addressOf(to), amountOf(buyers.Amount), 140000, types2.Transaction{
+		Type:    types2.FoundryExchange,
+		Buyer:   buyers,
+		Seller2: seller2s,
+	})
+}
+
+func nftExchangeMatchCall(buyer, seller, exchangerAuth []byte, to string) (*txCall, error) {
+	err := tools.CheckAddress("NftExchangeMatch() to", to)
+	if err != nil {
+		return nil, err
+	}
+	buyers, err := parseBuyer(buyer)
+	if err != nil {
+		return nil, err
+	}
+	sellers, err := parseSeller1(seller)
+	if err != nil {
+		return nil, err
+	}
+	exchangerAuths, err := parseExchangerAuth(exchangerAuth)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("NftExchangeMatch", addressOf(to), amountOf(buyers.Amount), 140000, types2.Transaction{
+		Type:          types2.NftExchangeMatch,
+		Buyer:         buyers,
+		Seller1:       sellers,
+		ExchangerAuth: exchangerAuths,
+	})
+}
+
+func foundryExchangeInitiatedCall(buyer, seller2, exchangerAuth []byte, to string) (*txCall, error) {
+	err := tools.CheckAddress("FoundryExchangeInitiated() to", to)
+	if err != nil {
+		return nil, err
+	}
+	buyers, err := parseBuyer(buyer)
+	if err != nil {
+		return nil, err
+	}
+	seller2s, err := parseSeller2(seller2)
+	if err != nil {
+		return nil, err
+	}
+	exchangerAuths, err := parseExchangerAuth(exchangerAuth)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("FoundryExchangeInitiated", addressOf(to), amountOf(buyers.Amount), 170000, types2.Transaction{
+		Type:          types2.FoundryExchangeInitiated,
+		Buyer:         buyers,
+		Seller2:       seller2s,
+		ExchangerAuth: exchangerAuths,
+	})
+}
+
+func nftDoesNotAuthorizeExchangesCall(buyer, seller1 []byte, to string) (*txCall, error) {
+	err := tools.CheckAddress("FtDoesNotAuthorizeExchanges() to", to)
+	if err != nil {
+		return nil, err
+	}
+	buyers, err := parseBuyer(buyer)
+	if err != nil {
+		return nil, err
+	}
+	seller1s, err := parseSeller1(seller1)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("NFTDoesNotAuthorizeExchanges", addressOf(to), amountOf(buyers.Amount), 130000, types2.Transaction{
+		Type:    types2.FtDoesNotAuthorizeExchanges,
+		Buyer:   buyers,
+		Seller1: seller1s,
+	})
+}
+
+func additionalPledgeAmountCall(value int64) (*txCall, error) {
+	return additionalPledgeAmountWeiCall(big.NewInt(value))
+}
+
+func additionalPledgeAmountWeiCall(value *big.Int) (*txCall, error) {
+	err := checkWei("AdditionalPledgeAmount", value)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("AdditionalPledgeAmount", nil, value, 55000, types2.Transaction{
+		Type: types2.AdditionalPledgeAmount,
+	})
+}
+
+func revokesPledgeAmountCall(value int64) (*txCall, error) {
+	return revokesPledgeAmountWeiCall(big.NewInt(value))
+}
+
+func revokesPledgeAmountWeiCall(value *big.Int) (*txCall, error) {
+	err := checkWei("RevokesPledgeAmount", value)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("RevokesPledgeAmount", nil, value, 55000, types2.Transaction{
+		Type: types2.RevokesPledgeAmount,
+	})
+}
+
+func voteOfficialNFTCall(dir, startIndex string, number uint64, royalty uint32, creator string) (*txCall, error) {
+	err := tools.CheckAddress("VoteOfficialNFT() creator", creator)
+	if err != nil {
+		return nil, err
+	}
+	return wormholesCall("VoteOfficialNFT", nil, nil, 60000, types2.Transaction{
+		Type:       types2.VoteOfficialNFT,
+		Dir:        dir,
+		StartIndex: startIndex,
+		Number:     number,
+		Royalty:    royalty,
+		Creator:    creator,
+	})
+}
+
+func voteOfficialNFTByApprovedExchangerCall(dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (*txCall, error) {
+	err := tools.CheckAddress("VoteOfficialNFTByApprovedExchanger() creator", creator)
+	if err != nil {
+		return nil, err
+	}
+	var exchangerAuths types2.ExchangerAuth
+	err = json.Unmarshal(exchangerAuth, &exchangerAuths)
+	if err != nil {
+		return nil, xerrors.New("the formate of exchangerAuth is wrong")
+	}
+	return wormholesCall("VoteOfficialNFTByApprovedExchanger", nil, nil, 60000, types2.Transaction{
+		Type:          types2.VoteOfficialNFTByApprovedExchanger,
+		Dir:           dir,
+		StartIndex:    startIndex,
+		Number:        number,
+		Royalty:       royalty,
+		Creator:       creator,
+		ExchangerAuth: &exchangerAuths,
+	})
+}
+
+func unforzenAccountCall() (*txCall, error) {
+	return wormholesCall("UnforzenAccount", nil, nil, 50000, types2.Transaction{
+		Type: types2.UnforzenAccount,
+	})
+}
+
+func accountDelegateCall(proxySign []byte, proxyAddress string) (*txCall, error) {
+	return wormholesCall("AccountDelegate", nil, nil, 70000, types2.Transaction{
+		Type:         types2.AccountDelegate,
+		ProxyAddress: proxyAddress,
+		ProxySign:    string(proxySign),
+	})
+}
+
+// TxBuilder builds unsigned wormholes transactions sent from the account From.
+// The gas limit of the transactions is the default of their type, Wormholes
+// estimates it with the node before sending.
+// It does not need a node connection, so together with Wallet.SignTx and
+// Wormholes.SendRawTransaction it allows to build and sign transactions on an
+// offline machine and broadcast them from another one.
+//
+// When GasFeeCap is set, EIP-1559 dynamic fee transactions are built for the
+// chain ChainID, otherwise legacy transactions paying GasPrice.
+//
+//	builder := &client.TxBuilder{From: account, Nonce: 12, GasPrice: big.NewInt(1000000000)}
+//	tx, err := builder.Transfer("0x0000000000000000000000000000000000000001", to)
+type TxBuilder struct {
+	From     common.Address
+	Nonce    uint64
+	ChainID  *big.Int
+	GasPrice *big.Int
+
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+}
+
+func (b *TxBuilder) build(c *txCall, err error) (*types.Transaction, error) {
+	if err != nil {
+		name := "TxBuilder"
+		if c != nil {
+			name = c.name
+		}
+		return nil, invalid(name, err)
+	}
+	to := c.recipient(b.From)
+	if b.GasFeeCap == nil {
+		return types.NewTransaction(b.Nonce, to, c.value, c.gasLimit, b.GasPrice, c.data), nil
+	}
+	if b.ChainID == nil {
+		return nil, invalid(c.name, xerrors.New("the chain id of a dynamic fee transaction is missing"))
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   b.ChainID,
+		Nonce:     b.Nonce,
+		GasTipCap: b.GasTipCap,
+		GasFeeCap: b.GasFeeCap,
+		Gas:       c.gasLimit,
+		To:        &to,
+		Value:     c.value,
+		Data:      c.data,
+	}), nil
+}
+
+// NormalTransaction builds an unsigned ERB transfer, see Wormholes.NormalTransaction
+func (b *TxBuilder) NormalTransaction(to string, value int64, data string) (*types.Transaction, error) {
+	return b.build(normalTransactionCall(to, value, data))
+}
+
+// Mint builds an unsigned NFT minting transaction, see Wormholes.Mint
+func (b *TxBuilder) Mint(royalty uint32, metaURL string, exchanger string) (*types.Transaction, error) {
+	return b.build(mintCall(royalty, metaURL, exchanger))
+}
+
+// Transfer builds an unsigned NFT transfer transaction, see Wormholes.Transfer
+func (b *TxBuilder) Transfer(wormAddress, to string) (*types.Transaction, error) {
+	return b.build(transferCall(wormAddress, to))
+}
+
+// Author builds an unsigned NFT authorization transaction, see Wormholes.Author
+func (b *TxBuilder) Author(wormAddress, to string) (*types.Transaction, error) {
+	return b.build(authorCall(wormAddress, to))
+}
+
+// AuthorRevoke builds an unsigned transaction cancelling an NFT authorization, see Wormholes.AuthorRevoke
+func (b *TxBuilder) AuthorRevoke(wormAddress, to string) (*types.Transaction, error) {
+	return b.build(authorRevokeCall(wormAddress, to))
+}
+
+// AccountAuthor builds an unsigned account authorization transaction, see Wormholes.AccountAuthor
+func (b *TxBuilder) AccountAuthor(to string) (*types.Transaction, error) {
+	return b.build(accountAuthorCall(to))
+}
+
+// AccountAuthorRevoke builds an unsigned transaction cancelling an account authorization, see Wormholes.AccountAuthorRevoke
+func (b *TxBuilder) AccountAuthorRevoke(to string) (*types.Transaction, error) {
+	return b.build(accountAuthorRevokeCall(to))
+}
+
+// SNFTToERB builds an unsigned SNFT conversion transaction, see Wormholes.SNFTToERB
+func (b *TxBuilder) SNFTToERB(wormAddress string) (*types.Transaction, error) {
+	return b.build(snftToERBCall(wormAddress))
+}
+
+// SNFTPledge builds an unsigned SNFT pledge transaction, see Wormholes.SNFTPledge
+func (b *TxBuilder) SNFTPledge(snftAddress string) (*types.Transaction, error) {
+	return b.build(snftPledgeCall(snftAddress))
+}
+
+// SNFTRevokesPledge builds an unsigned transaction revoking an SNFT pledge, see Wormholes.SNFTRevokesPledge
+func (b *TxBuilder) SNFTRevokesPledge(snftAddress string) (*types.Transaction, error) {
+	return b.build(snftRevokesPledgeCall(snftAddress))
+}
+
+// TokenPledge builds an unsigned ERB pledge transaction, see Wormholes.TokenPledge
+func (b *TxBuilder) TokenPledge(proxySign []byte, proxyAddress string, value int64) (*types.Transaction, error) {
+	return b.build(tokenPledgeCall(proxySign, proxyAddress, value))
+}
+
+// TokenRevokesPledge builds an unsigned transaction revoking an ERB pledge, see Wormholes.TokenRevokesPledge
+func (b *TxBuilder) TokenRevokesPledge(value int64) (*types.Transaction, error) {
+	return b.build(tokenRevokesPledgeCall(value))
+}
+
+// Open builds an unsigned transaction opening an exchange, see Wormholes.Open
+func (b *TxBuilder) Open(feeRate uint32, name, url string) (*types.Transaction, error) {
+	return b.build(openCall(feeRate, name, url))
+}
+
+// Close builds an unsigned transaction closing an exchange, see Wormholes.Close
+func (b *TxBuilder) Close() (*types.Transaction, error) {
+	return b.build(closeCall())
+}
+
+// TransactionNFT builds an unsigned minted NFT trade, see Wormholes.TransactionNFT
+func (b *TxBuilder) TransactionNFT(buyer []byte, to string) (*types.Transaction, error) {
+	return b.build(transactionNFTCall(buyer, to))
+}
+
+// BuyerInitiatingTransaction builds an unsigned minted NFT trade initiated by the buyer, see Wormholes.BuyerInitiatingTransaction
+func (b *TxBuilder) BuyerInitiatingTransaction(seller1 []byte) (*types.Transaction, error) {
+	return b.build(buyerInitiatingTransactionCall(seller1))
+}
+
+// FoundryTradeBuyer builds an unsigned unminted NFT trade initiated by the buyer, see Wormholes.FoundryTradeBuyer
+func (b *TxBuilder) FoundryTradeBuyer(seller2 []byte) (*types.Transaction, error) {
+	return b.build(foundryTradeBuyerCall(seller2))
+}
+
+// FoundryExchange builds an unsigned unminted NFT trade, see Wormholes.FoundryExchange
+func (b *TxBuilder) FoundryExchange(buyer, seller2 []byte, to string) (*types.Transaction, error) {
+	return b.build(foundryExchangeCall(buyer, seller2, to))
+}
+
+// NftExchangeMatch builds an unsigned minted NFT trade of an authorized exchange, see Wormholes.NftExchangeMatch
+func (b *TxBuilder) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to string) (*types.Transaction, error) {
+	return b.build(nftExchangeMatchCall(buyer, seller, exchangerAuth, to))
+}
+
+// FoundryExchangeInitiated builds an unsigned unminted NFT trade of an authorized exchange, see Wormholes.FoundryExchangeInitiated
+func (b *TxBuilder) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []byte, to string) (*types.Transaction, error) {
+	return b.build(foundryExchangeInitiatedCall(buyer, seller2, exchangerAuth, to))
+}
+
+// NFTDoesNotAuthorizeExchanges builds an unsigned trade of an NFT not authorized to the exchange, see Wormholes.NFTDoesNotAuthorizeExchanges
+func (b *TxBuilder) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to string) (*types.Transaction, error) {
+	return b.build(nftDoesNotAuthorizeExchangesCall(buyer, seller1, to))
+}
+
+// AdditionalPledgeAmount builds an unsigned transaction increasing the exchange pledge, see Wormholes.AdditionalPledgeAmount
+func (b *TxBuilder) AdditionalPledgeAmount(value int64) (*types.Transaction, error) {
+	return b.build(additionalPledgeAmountCall(value))
+}
+
+// RevokesPledgeAmount builds an unsigned transaction decreasing the exchange pledge, see Wormholes.RevokesPledgeAmount
+func (b *TxBuilder) RevokesPledgeAmount(value int64) (*types.Transaction, error) {
+	return b.build(revokesPledgeAmountCall(value))
+}
+
+// VoteOfficialNFT builds an unsigned SNFT injection transaction, see Wormholes.VoteOfficialNFT
+func (b *TxBuilder) VoteOfficialNFT(dir, startIndex string, number uint64, royalty uint32, creator string) (*types.Transaction, error) {
+	return b.build(voteOfficialNFTCall(dir, startIndex, number, royalty, creator))
+}
+
+// VoteOfficialNFTByApprovedExchanger builds an unsigned SNFT injection transaction of an approved exchange, see Wormholes.VoteOfficialNFTByApprovedExchanger
+func (b *TxBuilder) VoteOfficialNFTByApprovedExchanger(dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (*types.Transaction, error) {
+	return b.build(voteOfficialNFTByApprovedExchangerCall(dir, startIndex, number, royalty, creator, exchangerAuth))
+}
+
+// UnforzenAccount builds an unsigned transaction changing the revenue model, see Wormholes.UnforzenAccount
+func (b *TxBuilder) UnforzenAccount() (*types.Transaction, error) {
+	return b.build(unforzenAccountCall())
+}
+
+// AccountDelegate builds an unsigned account delegation transaction, see Wormholes.AccountDelegate
+func (b *TxBuilder) AccountDelegate(proxySign []byte, proxyAddress string) (*types.Transaction, error) {
+	return b.build(accountDelegateCall(proxySign, proxyAddress))
+}
@@ -0,0 +1,146 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/tools"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
+)
+
+// ErrNotWormholes is returned when decoding a transaction whose data has no wormholes prefix
+var ErrNotWormholes = xerrors.New("not a wormholes transaction")
+
+// DecodedTransaction is a wormholes transaction read back from the chain
+type DecodedTransaction struct {
+	Hash      common.Hash
+	Operation string // name of the transaction type, such as "Mint" or "NftExchangeMatch"
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Payload   *types2.Transaction
+}
+
+// DecodeTransaction decodes the wormholes payload of tx, as returned by
+// BlockByNumber or TransactionInBlock, and recovers its sender.
+// Transactions without the wormholes prefix return ErrNotWormholes.
+func DecodeTransaction(tx *types.Transaction) (*DecodedTransaction, error) {
+	payload, err := DecodeData(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if tx.To() == nil {
+		return nil, xerrors.New("the wormholes transaction has no recipient")
+	}
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to recover the sender of the wormholes transaction: %w", err)
+	}
+	name, _ := types2.TypeName(payload.Type)
+	return &DecodedTransaction{
+		Hash:      tx.Hash(),
+		Operation: name,
+		From:      from,
+		To:        *tx.To(),
+		Value:     tx.Value(),
+		Payload:   payload,
+	}, nil
+}
+
+// DecodeData decodes and validates the data of a wormholes transaction
+func DecodeData(data []byte) (*types2.Transaction, error) {
+	if !bytes.HasPrefix(data, []byte(wormholesPrefix)) {
+		return nil, ErrNotWormholes
+	}
+	var payload types2.Transaction
+	err := json.Unmarshal(data[len(wormholesPrefix):], &payload)
+	if err != nil {
+		return nil, xerrors.Errorf("the formate of wormholes data is wrong: %w", err)
+	}
+	err = validatePayload(&payload)
+	if err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// validatePayload checks that the fields required by the type of the payload are present
+func validatePayload(payload *types2.Transaction) error {
+	name, ok := types2.TypeName(payload.Type)
+	if !ok {
+		return xerrors.Errorf("unknown wormholes transaction type %d", payload.Type)
+	}
+	switch payload.Type {
+	case types2.Mint:
+		if payload.Exchanger != "" {
+			return tools.CheckAddress(name+" exchanger", payload.Exchanger)
+		}
+	case types2.Transfer, types2.Author, types2.AuthorRevoke, types2.SNFTToERB,
+		types2.SNFTPledge, types2.SNFTRevokesPledge:
+		return tools.CheckHex(name+" nft_address", payload.NFTAddress)
+	case types2.TransactionNFT:
+		return checkOrders(name, payload, true, false, false, false)
+	case types2.BuyerInitiatingTransaction:
+		return checkOrders(name, payload, false, true, false, false)
+	case types2.FoundryTradeBuyer:
+		return checkOrders(name, payload, false, false, true, false)
+	case types2.FoundryExchange:
+		return checkOrders(name, payload, true, false, true, false)
+	case types2.NftExchangeMatch:
+		return checkOrders(name, payload, true, true, false, true)
+	case types2.FoundryExchangeInitiated:
+		return checkOrders(name, payload, true, false, true, true)
+	case types2.FtDoesNotAuthorizeExchanges:
+		return checkOrders(name, payload, true, true, false, false)
+	case types2.VoteOfficialNFT:
+		return tools.CheckAddress(name+" creator", payload.Creator)
+	case types2.VoteOfficialNFTByApprovedExchanger:
+		err := tools.CheckAddress(name+" creator", payload.Creator)
+		if err != nil {
+			return err
+		}
+		return checkOrders(name, payload, false, false, false, true)
+	}
+	return nil
+}
+
+// checkOrders checks that the signed orders a trade needs are present and well formed
+func checkOrders(name string, payload *types2.Transaction, buyer, seller1, seller2, exchangerAuth bool) error {
+	if buyer {
+		if payload.Buyer == nil {
+			return xerrors.Errorf("%s has no buyer", name)
+		}
+		err := tools.CheckHex(name+" buyer.block_number", payload.Buyer.BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+	if seller1 {
+		if payload.Seller1 == nil {
+			return xerrors.Errorf("%s has no seller1", name)
+		}
+		err := tools.CheckHex(name+" seller1.block_number", payload.Seller1.BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+	if seller2 {
+		if payload.Seller2 == nil {
+			return xerrors.Errorf("%s has no seller2", name)
+		}
+		err := tools.CheckHex(name+" seller2.block_number", payload.Seller2.BlockNumber)
+		if err != nil {
+			return err
+		}
+	}
+	if exchangerAuth {
+		if payload.ExchangerAuth == nil {
+			return xerrors.Errorf("%s has no exchanger_auth", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+// SetDryRun selects whether the APIs methods only simulate their transactions.
+// A simulated transaction is executed by the node with eth_call against the pending
+// state and never broadcast: the methods return an empty hash when it would succeed,
+// and the error of the node otherwise.
+func (worm *Wormholes) SetDryRun(enabled bool) {
+	worm.dryRun = enabled
+}
+
+// DryRun returns a client sharing the connection, the wallet and the settings of worm
+// whose APIs methods only simulate their transactions, see SetDryRun
+//
+//	if _, err := worm.DryRun().Transfer(nftAddress, to); err != nil {
+//		return err
+//	}
+//	hash, err := worm.Transfer(nftAddress, to)
+func (worm *Wormholes) DryRun() *Wormholes {
+	dry := *worm
+	dry.dryRun = true
+	return &dry
+}
+
+// callMsg returns the message of the transaction described by c, with the fees of the
+// builder, so the node checks that the sender can pay for its gas
+func (b *TxBuilder) callMsg(c *txCall) ethereum.CallMsg {
+	to := c.recipient(b.From)
+	return ethereum.CallMsg{
+		From:      b.From,
+		To:        &to,
+		Gas:       c.gasLimit,
+		GasPrice:  b.GasPrice,
+		GasFeeCap: b.GasFeeCap,
+		GasTipCap: b.GasTipCap,
+		Value:     c.value,
+		Data:      c.data,
+	}
+}
+
+// simulate executes the transaction described by c with eth_call against the pending state
+func (worm *Wormholes) simulate(ctx context.Context, builder *TxBuilder, c *txCall) error {
+	_, err := worm.CallContract(ctx, builder.callMsg(c), big.NewInt(-1))
+	if err != nil {
+		worm.logger.Warn("transaction simulation failed", "op", c.name, "err", err)
+		return sendError(c.name, err, c.payload != nil)
+	}
+	worm.logger.Info("transaction simulated", "op", c.name, "from", builder.From)
+	return nil
+}
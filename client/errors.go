@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/xerrors"
+)
+
+// The kinds of errors returned by the client. Errors of the transactions and
+// queries are *OpError values matching one of them with errors.Is, for example
+//
+//	_, err := worm.Mint(10, metaURL, "")
+//	if errors.Is(err, client.ErrInsufficientFunds) {
+//		...
+//	}
+var (
+	// ErrValidation is returned when the arguments of a call are malformed
+	ErrValidation = xerrors.New("invalid argument")
+	// ErrInsufficientFunds is returned when the account can not pay the value and the gas of a transaction
+	ErrInsufficientFunds = xerrors.New("insufficient funds")
+	// ErrNonceConflict is returned when the node rejects the nonce of a transaction
+	ErrNonceConflict = xerrors.New("nonce conflict")
+	// ErrUnderpriced is returned when the gas price of a transaction, or of the replacement
+	// of a pending transaction, is too low
+	ErrUnderpriced = xerrors.New("transaction underpriced")
+	// ErrPayloadRejected is returned when the node rejects a wormholes transaction
+	ErrPayloadRejected = xerrors.New("wormholes transaction rejected")
+	// ErrNonceGap is returned for a transaction of a batch sent after a rejected one, it
+	// waits in the queue of the node for the nonce of the rejected transaction
+	ErrNonceGap = xerrors.New("nonce gap")
+	// ErrTransport is returned when the node can not be reached
+	ErrTransport = xerrors.New("rpc transport failure")
+)
+
+// errNoConnection is returned by the calls of a client created without a node url
+// or whose node could not be dialed
+var errNoConnection = xerrors.New("the client is not connected to a node")
+
+// OpError is the error of the operation Op, such as "Mint" or "GetAccountInfo"
+type OpError struct {
+	Op   string
+	Kind error // one of the error kinds above, nil when the error is not classified
+	Err  error
+}
+
+func (e *OpError) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the kind target
+func (e *OpError) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// opError wraps err as an error of the operation op. The kind of an OpError is
+// kept, so the error of a query reports the transaction it was made for.
+func opError(op string, kind error, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *OpError
+	if xerrors.As(err, &e) {
+		if kind == nil {
+			kind = e.Kind
+		}
+		err = e.Err
+	}
+	return &OpError{Op: op, Kind: kind, Err: err}
+}
+
+// invalid wraps the error of malformed arguments of the operation op
+func invalid(op string, err error) error {
+	return opError(op, ErrValidation, err)
+}
+
+// rpcError wraps the error of the request sent to the node for the operation op
+func rpcError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var kind error
+	if isTransportError(err) {
+		kind = ErrTransport
+	}
+	return opError(op, kind, err)
+}
+
+// sendError wraps the error of the node rejecting the transaction sent for the operation op,
+// wormholes reports whether it carries a wormholes payload
+func sendError(op string, err error, wormholes bool) error {
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	var kind error
+	switch {
+	case strings.Contains(msg, "insufficient funds"):
+		kind = ErrInsufficientFunds
+	case strings.Contains(msg, "nonce too low"), strings.Contains(msg, "nonce too high"):
+		kind = ErrNonceConflict
+	case strings.Contains(msg, "underpriced"):
+		kind = ErrUnderpriced
+	case isTransportError(err):
+		kind = ErrTransport
+	case wormholes && isNodeError(err):
+		kind = ErrPayloadRejected
+	}
+	return opError(op, kind, err)
+}
+
+// isNodeError reports whether err is an error response of the node
+func isNodeError(err error) bool {
+	var rpcErr rpc.Error
+	return xerrors.As(err, &rpcErr)
+}
+
+// isTransportError reports whether err means the node could not be reached or
+// did not answer with a JSON-RPC response
+func isTransportError(err error) bool {
+	if xerrors.Is(err, context.Canceled) || xerrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isNodeError(err) {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	var netErr net.Error
+	var urlErr *url.Error
+	return xerrors.As(err, &httpErr) || xerrors.As(err, &netErr) || xerrors.As(err, &urlErr) ||
+		xerrors.Is(err, rpc.ErrClientQuit) || xerrors.Is(err, io.EOF) || xerrors.Is(err, io.ErrUnexpectedEOF) ||
+		xerrors.Is(err, errNoConnection)
+}
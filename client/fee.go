@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"golang.org/x/xerrors"
+)
+
+// ErrLondonUnsupported is returned when the node does not provide a base fee,
+// that is when the London fork is not active.
+var ErrLondonUnsupported = xerrors.New("the node does not support dynamic fee transactions")
+
+// SuggestDynamicFee suggests the gas tip cap and the gas fee cap of a dynamic fee transaction.
+// The tip is the one suggested by eth_maxPriorityFeePerGas, the fee cap leaves room
+// for the base fee of the pending block, taken from eth_feeHistory, to double.
+func (worm *Wormholes) SuggestDynamicFee(ctx context.Context) (gasTipCap, gasFeeCap *big.Int, err error) {
+	history, err := worm.FeeHistory(ctx, 1, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(history.BaseFee) == 0 {
+		return nil, nil, ErrLondonUnsupported
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if baseFee == nil || baseFee.Sign() == 0 {
+		return nil, nil, ErrLondonUnsupported
+	}
+	gasTipCap, err = worm.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	gasFeeCap = new(big.Int).Add(gasTipCap, new(big.Int).Mul(baseFee, big.NewInt(2)))
+	return gasTipCap, gasFeeCap, nil
+}
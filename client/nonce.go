@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nonceRetries is the number of times a transaction is resent with a resynced
+// nonce when the node rejects its nonce
+const nonceRetries = 3
+
+// nonceManager hands out the nonces of the accounts sending through a Wormholes client.
+// The first nonce of an account is read from the pending state of the node, the next
+// ones are counted locally, so transactions sent concurrently or back-to-back from the
+// same account get sequential nonces.
+type nonceManager struct {
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonce
+}
+
+type accountNonce struct {
+	mu     sync.Mutex
+	synced bool
+	next   uint64
+}
+
+func newNonceManager() *nonceManager {
+	return &nonceManager{accounts: make(map[common.Address]*accountNonce)}
+}
+
+func (m *nonceManager) account(account common.Address) *accountNonce {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.accounts[account]
+	if !ok {
+		s = new(accountNonce)
+		m.accounts[account] = s
+	}
+	return s
+}
+
+// next hands out the next nonce of account, fetching it from the node when the
+// account is not synced
+func (m *nonceManager) next(ctx context.Context, account common.Address, pending func(context.Context, common.Address) (uint64, error)) (uint64, error) {
+	s := m.account(account)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.synced {
+		nonce, err := pending(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+		s.next, s.synced = nonce, true
+	}
+	nonce := s.next
+	s.next++
+	return nonce, nil
+}
+
+// release gives back a nonce which was handed out but not used by a transaction.
+// If later nonces were handed out meanwhile, the account is resynced instead.
+func (m *nonceManager) release(account common.Address, nonce uint64) {
+	s := m.account(account)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.synced && s.next == nonce+1 {
+		s.next = nonce
+	} else {
+		s.synced = false
+	}
+}
+
+// reset makes the next nonce of account be read from the node again
+func (m *nonceManager) reset(account common.Address) {
+	s := m.account(account)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced = false
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs the transactions and the orders of an account.
+// Implementations may keep the key in memory, in a keystore, a remote signer or an HSM.
+type Signer interface {
+	// Address returns the account of the signer
+	Address() common.Address
+	// SignTx signs the transaction for the chain chainID
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignHash signs a 32 bytes hash, the signature is in the [R || S || V] format where V is 0 or 1
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// KeySigner is a Signer holding a raw private key
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner creates a Signer from a hex encoded private key, with or without 0x prefix
+func NewKeySigner(priKey string) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimPrefix(priKey, "0x"), "0X"))
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySignerFromECDSA(key), nil
+}
+
+// NewKeySignerFromECDSA creates a Signer from a private key
+func NewKeySignerFromECDSA(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *KeySigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.key)
+}
+
+var _ Signer = &KeySigner{}
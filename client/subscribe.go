@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"golang.org/x/xerrors"
+)
+
+// DefaultResubscribeBackoff is the longest wait between two attempts to subscribe
+// again after the subscription of a node connection is lost
+const DefaultResubscribeBackoff = 30 * time.Second
+
+// SetResubscribeBackoff sets the longest wait between two attempts to subscribe again
+// after a subscription is lost, DefaultResubscribeBackoff if 0.
+func (worm *Wormholes) SetResubscribeBackoff(backoff time.Duration) {
+	worm.resubscribeBackoff = backoff
+}
+
+// SubscribeNewHead subscribes to the headers of the new blocks of the chain.
+// Subscriptions need a websocket or IPC connection, over HTTP the error matches
+// rpc.ErrNotificationsUnsupported.
+//
+// When the connection is lost the client reconnects and subscribes again, waiting
+// at most the resubscribe backoff between two attempts, the blocks mined meanwhile are
+// not delivered. The channel is not closed, the subscription ends with Unsubscribe.
+//
+//	heads := make(chan *types.Header, 16)
+//	sub, err := worm.SubscribeNewHead(ctx, heads)
+//	if err != nil {
+//		return err
+//	}
+//	defer sub.Unsubscribe()
+//	for head := range heads {
+//		...
+//	}
+func (worm *Wormholes) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error) {
+	return worm.subscribe(ctx, "SubscribeNewHead", ch, "newHeads")
+}
+
+// SubscribeNewPendingTransactions subscribes to the hashes of the transactions entering
+// the pool of the node, it is resubscribed as SubscribeNewHead.
+func (worm *Wormholes) SubscribeNewPendingTransactions(ctx context.Context, ch chan<- common.Hash) (ethereum.Subscription, error) {
+	return worm.subscribe(ctx, "SubscribeNewPendingTransactions", ch, "newPendingTransactions")
+}
+
+// SubscribeFilterLogs subscribes to the logs of the new blocks matching q,
+// it is resubscribed as SubscribeNewHead.
+func (worm *Wormholes) SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	arg, err := toFilterArg(q)
+	if err != nil {
+		return nil, invalid("SubscribeFilterLogs", err)
+	}
+	return worm.subscribe(ctx, "SubscribeFilterLogs", ch, "logs", arg)
+}
+
+// subscribe subscribes to the notifications args of the node, sent to the channel ch.
+// The first subscription is made with ctx and its error returned, the next ones are
+// made in the background by an event.ResubscribeErr loop.
+func (worm *Wormholes) subscribe(ctx context.Context, op string, ch interface{}, args ...interface{}) (ethereum.Subscription, error) {
+	if worm.c == nil {
+		return nil, rpcError(op, worm.connError())
+	}
+	first, err := worm.c.EthSubscribe(ctx, ch, args...)
+	if err != nil {
+		return nil, rpcError(op, err)
+	}
+	backoff := worm.resubscribeBackoff
+	if backoff <= 0 {
+		backoff = DefaultResubscribeBackoff
+	}
+	return event.ResubscribeErr(backoff, func(ctx context.Context, lastErr error) (event.Subscription, error) {
+		if first != nil {
+			sub := first
+			first = nil
+			return sub, nil
+		}
+		worm.logger.Warn("subscription lost, subscribing again", "op", op, "err", lastErr)
+		sub, err := worm.c.EthSubscribe(ctx, ch, args...)
+		if err != nil {
+			worm.logger.Warn("subscription failed", "op", op, "err", err)
+			return nil, err
+		}
+		worm.logger.Info("subscribed again", "op", op)
+		return sub, nil
+	}), nil
+}
+
+// toFilterArg returns the filter of the logs matching q, as ethclient
+func toFilterArg(q ethereum.FilterQuery) (interface{}, error) {
+	arg := map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+	if q.BlockHash != nil {
+		if q.FromBlock != nil || q.ToBlock != nil {
+			return nil, xerrors.New("cannot specify both BlockHash and FromBlock/ToBlock")
+		}
+		arg["blockHash"] = *q.BlockHash
+		return arg, nil
+	}
+	if q.FromBlock == nil {
+		arg["fromBlock"] = "0x0"
+	} else {
+		arg["fromBlock"] = toBlockNumArg(q.FromBlock)
+	}
+	arg["toBlock"] = toBlockNumArg(q.ToBlock)
+	return arg, nil
+}
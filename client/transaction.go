@@ -1,197 +1,262 @@
 package client
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/wormholes-org/wormholes-client/tools"
-	types2 "github.com/wormholes-org/wormholes-client/types"
 	"golang.org/x/xerrors"
-	"log"
-	"math/big"
-	"strings"
 )
 
-// NormalTransaction
-//	Parameter Description
-//  to 			Account address
-//  value		transaction amount
-//  data
-func (worm *Wormholes) NormalTransaction(to string, value int64, data string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+// NewTxBuilder returns a TxBuilder for the wallet account, the nonce and the fees
+// are taken from the pending state of the node.
+// With SetDynamicFee the builder is set up for dynamic fee transactions, unless
+// the node does not support them.
+func (worm *Wormholes) NewTxBuilder(ctx context.Context) (*TxBuilder, error) {
+	signer, err := worm.Signer()
 	if err != nil {
-		log.Println("NormalTransaction() priKeyToAddress err ", err)
-		return "", err
+		return nil, invalid("NewTxBuilder", err)
 	}
-
-	toAddr := common.HexToAddress(to)
+	account := signer.Address()
 	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(51000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
 	if err != nil {
-		log.Println("NormalTransaction() suggestGasPrice err ", err)
-		return "", err
+		return nil, err
 	}
-
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	charge := new(big.Int).Mul(big.NewInt(value), wei)
-	tx := types.NewTransaction(nonce, toAddr, charge, gasLimit, gasPrice, []byte(data))
-	chainID, err := worm.NetworkID(ctx)
+	builder, err := worm.newTxBuilder(ctx, account)
 	if err != nil {
-		log.Println("NormalTransaction() networkID err=", err)
-		return "", err
+		return nil, err
 	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	builder.Nonce = nonce
+	return builder, nil
+}
+
+// newTxBuilder returns a TxBuilder for account with the chain ID and the fees set
+func (worm *Wormholes) newTxBuilder(ctx context.Context, account common.Address) (*TxBuilder, error) {
+	chainID, err := worm.NetworkID(ctx)
 	if err != nil {
-		log.Println("NormalTransaction() signTx err ", err)
-		return "", err
+		return nil, err
 	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("NormalTransaction() sendTransaction err ", err)
-		return "", err
+	builder := &TxBuilder{
+		From:    account,
+		ChainID: chainID,
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// Mint NFT user minting
-//	Users can use this transaction to create an NFT on the wormholes chain
-//
-//	Parameter Description
-//	royalty: 10,																					Royalty, formatted as an integer
-//	metaURL: "/ipfs/ddfd90be9408b4",	NFT metadata address
-//	exchanger:"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4",							The exchange when the NFT is minted, the format is a string. When this field is filled, the exchange will exclusively own the NFT. If it is not filled in, no exchange will exclusively own the NFT
-func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (string, error) {
-	if exchanger != "" {
-		err := tools.CheckAddress("Mint() exchanger", exchanger)
-		if err != nil {
-			return "", err
+	if worm.dynamicFee {
+		builder.GasTipCap, builder.GasFeeCap, err = worm.SuggestDynamicFee(ctx)
+		if err == nil {
+			return builder, nil
 		}
+		worm.logger.Warn("dynamic fee unavailable, falling back to a legacy transaction", "err", err)
 	}
-
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	builder.GasPrice, err = worm.SuggestGasPrice(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	return builder, nil
+}
 
-	nonce, err := worm.PendingNonceAt(ctx, account)
+// SendRawTransaction broadcasts a signed transaction in its binary encoding,
+// as produced by types.Transaction.MarshalBinary, and returns its hash.
+func (worm *Wormholes) SendRawTransaction(ctx context.Context, rawTx []byte) (string, error) {
+	return worm.sendRawTransaction(ctx, "SendRawTransaction", rawTx)
+}
 
-	gasLimit := uint64(60000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
+// sendRawTransaction broadcasts rawTx for the operation op, the rejections of the node
+// are returned as the error kinds of the client
+func (worm *Wormholes) sendRawTransaction(ctx context.Context, op string, rawTx []byte) (string, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return "", invalid(op, err)
+	}
+	if worm.c == nil {
+		return "", rpcError(op, worm.connError())
+	}
+	err := worm.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(rawTx))
 	if err != nil {
-		log.Println("Mint() suggestGasPrice err ", err)
-		return "", err
+		return "", sendError(op, err, bytes.HasPrefix(tx.Data(), []byte(wormholesPrefix)))
 	}
+	return strings.ToLower(tx.Hash().String()), nil
+}
+
+// estimateGas returns the gas estimated by the node for c increased by the gas margin,
+// the default gas limit of c is used when the node can not estimate it.
+func (worm *Wormholes) estimateGas(ctx context.Context, from common.Address, c *txCall) uint64 {
+	gas, err := worm.EstimateGas(ctx, estimateMsg(from, c))
+	return worm.gasLimit(c, gas, err)
+}
 
-	transaction := types2.Transaction{
-		Type:      types2.Mint,
-		Royalty:   royalty,
-		MetaURL:   metaURL,
-		Exchanger: exchanger,
-		Version:   types2.WormHolesVersion,
+// estimateMsg returns the message whose gas is estimated for c sent from the account from
+func estimateMsg(from common.Address, c *txCall) ethereum.CallMsg {
+	to := c.recipient(from)
+	return ethereum.CallMsg{
+		From:  from,
+		To:    &to,
+		Value: c.value,
+		Data:  c.data,
 	}
+}
 
-	data, err := json.Marshal(transaction)
+// gasLimit returns the gas estimated for c increased by the gas margin, or the default
+// gas limit of c when the estimation failed with err
+func (worm *Wormholes) gasLimit(c *txCall, gas uint64, err error) uint64 {
 	if err != nil {
-		log.Println("Mint() failed to format wormholes data")
-		return "", err
+		worm.logger.Warn("gas estimation failed, using the default gas limit", "op", c.name, "gas", c.gasLimit, "err", err)
+		return c.gasLimit
 	}
+	return gas + gas*worm.gasMargin/100
+}
 
-	tx_data := append([]byte("wormholes:"), data...)
-
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
+// transact builds, signs and broadcasts the wormholes transaction described by c.
+// The nonce is handed out by the nonce manager, when the node rejects it the
+// account is resynced and the transaction sent again. The nonce is given back only
+// when the transaction never reached the node or was rejected by it; after a
+// transport error or a timeout the node may hold it, so the account is resynced.
+func (worm *Wormholes) transact(ctx context.Context, c *txCall) (string, error) {
+	signer, err := worm.Signer()
 	if err != nil {
-		log.Println("Mint() networkID err ", err)
-		return "", err
+		worm.logger.Error("no signer", "op", c.name, "err", err)
+		return "", invalid(c.name, err)
+	}
+	account := signer.Address()
+	if worm.verifyOrders {
+		err = verifyOrders(c, account)
+		if err != nil {
+			worm.logger.Warn("order verification failed", "op", c.name, "err", err)
+			return "", invalid(c.name, err)
+		}
 	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+	err = worm.checkExpiry(ctx, c)
 	if err != nil {
-		log.Println("Mint() signTx err ", err)
-		return "", err
+		worm.logger.Warn("order expiry check failed", "op", c.name, "err", err)
+		return "", opError(c.name, nil, err)
 	}
-	err = worm.SendTransaction(ctx, signedTx)
+	builder, err := worm.newTxBuilder(ctx, account)
 	if err != nil {
-		log.Println("Mint() sendTransaction err ", err)
-		return "", err
+		worm.logger.Error("failed to prepare the transaction", "op", c.name, "err", err)
+		return "", opError(c.name, nil, err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
+	c.gasLimit = worm.estimateGas(ctx, account, c)
+	if worm.dryRun {
+		return "", worm.simulate(ctx, builder, c)
+	}
+	worm.logger.Debug("sending transaction", "op", c.name, "from", account, "chainID", builder.ChainID, "gas", c.gasLimit)
 
-// Transfer NFT transfer
-// 	Change ownership of NFTs
-//
-//	Parameter Description
-//	wormAddress: "0x8000000000000000000000000000000000000001",  worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
-//	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",  Target NFT user address
-func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("Transfer() wormAddress", wormAddress)
-	if err != nil {
-		return "", err
+	for attempt := 0; ; attempt++ {
+		builder.Nonce, err = worm.nonces.next(ctx, account, worm.PendingNonceAt)
+		if err != nil {
+			worm.logger.Error("failed to get the nonce", "op", c.name, "err", err)
+			return "", opError(c.name, nil, err)
+		}
+		rawTx, err := worm.signCall(builder, c)
+		if err != nil {
+			// the transaction never reached the node
+			worm.nonces.release(account, builder.Nonce)
+			return "", opError(c.name, nil, err)
+		}
+		hash, err := worm.sendRawTransaction(ctx, c.name, rawTx)
+		switch {
+		case err == nil:
+			worm.logger.Info("transaction sent", "op", c.name, "hash", hash, "nonce", builder.Nonce)
+			return hash, nil
+		case nonceTaken(err):
+			worm.nonces.reset(account)
+			if attempt == nonceRetries {
+				return "", opError(c.name, nil, err)
+			}
+			worm.logger.Warn("nonce rejected, resyncing", "op", c.name, "nonce", builder.Nonce, "err", err)
+		case isNodeError(err):
+			worm.logger.Error("transaction rejected", "op", c.name, "nonce", builder.Nonce, "err", err)
+			worm.nonces.release(account, builder.Nonce)
+			return "", opError(c.name, nil, err)
+		default:
+			// the node may hold the transaction, its nonce is read from the node again
+			worm.logger.Error("failed to send the transaction", "op", c.name, "nonce", builder.Nonce, "err", err)
+			worm.nonces.reset(account)
+			return "", opError(c.name, nil, err)
+		}
 	}
-	err = tools.CheckAddress("Transfer() to", to)
+}
+
+// signCall builds the transaction described by c with builder, signs it and returns
+// its binary encoding
+func (worm *Wormholes) signCall(builder *TxBuilder, c *txCall) ([]byte, error) {
+	tx, err := builder.build(c, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+	signedTx, err := worm.SignTx(tx, builder.ChainID)
 	if err != nil {
-		return "", err
+		worm.logger.Error("failed to sign the transaction", "op", c.name, "err", err)
+		return nil, err
 	}
+	return signedTx.MarshalBinary()
+}
 
-	toAddr := common.HexToAddress(to)
+// nonceTaken reports whether the node rejected a transaction because its nonce is
+// already used, by a mined transaction or by a pending one it would replace
+func nonceTaken(err error) bool {
+	return xerrors.Is(err, ErrNonceConflict) ||
+		strings.Contains(strings.ToLower(err.Error()), "replacement transaction underpriced")
+}
 
-	nonce, err := worm.PendingNonceAt(ctx, account)
+// NormalTransaction
+//	Parameter Description
+//  to 			Account address
+//  value		transaction amount
+//  data
+func (worm *Wormholes) NormalTransaction(to string, value int64, data string) (string, error) {
+	return worm.NormalTransactionContext(context.Background(), to, value, data)
+}
 
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
+// NormalTransactionContext is like NormalTransaction but uses ctx for the requests sent to the node
+func (worm *Wormholes) NormalTransactionContext(ctx context.Context, to string, value int64, data string) (string, error) {
+	c, err := normalTransactionCall(to, value, data)
 	if err != nil {
-		log.Println("Transfer() suggestGasPrice err ", err)
-		return "", err
+		return "", invalid("NormalTransaction", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	transaction := types2.Transaction{
-		Type:       types2.Transfer,
-		NFTAddress: wormAddress,
-		Version:    types2.WormHolesVersion,
-	}
+// Mint NFT user minting
+//	Users can use this transaction to create an NFT on the wormholes chain
+//
+//	Parameter Description
+//	royalty: 10,																					Royalty, formatted as an integer
+//	metaURL: "/ipfs/ddfd90be9408b4",	NFT metadata address
+//	exchanger:"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4",							The exchange when the NFT is minted, the format is a string. When this field is filled, the exchange will exclusively own the NFT. If it is not filled in, no exchange will exclusively own the NFT
+func (worm *Wormholes) Mint(royalty uint32, metaURL string, exchanger string) (string, error) {
+	return worm.MintContext(context.Background(), royalty, metaURL, exchanger)
+}
 
-	data, err := json.Marshal(transaction)
+// MintContext is like Mint but uses ctx for the requests sent to the node
+func (worm *Wormholes) MintContext(ctx context.Context, royalty uint32, metaURL string, exchanger string) (string, error) {
+	c, err := mintCall(royalty, metaURL, exchanger)
 	if err != nil {
-		log.Println("Transfer() failed to format wormholes data")
-		return "", err
+		return "", invalid("Mint", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	tx_data := append([]byte("wormholes:"), data...)
-
-	fmt.Println(string(tx_data))
+// Transfer NFT transfer
+// 	Change ownership of NFTs
+//
+//	Parameter Description
+//	wormAddress: "0x8000000000000000000000000000000000000001",  worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
+//	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",  Target NFT user address
+func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
+	return worm.TransferContext(context.Background(), wormAddress, to)
+}
 
-	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("Transfer() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("Transfer() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// TransferContext is like Transfer but uses ctx for the requests sent to the node
+func (worm *Wormholes) TransferContext(ctx context.Context, wormAddress, to string) (string, error) {
+	c, err := transferCall(wormAddress, to)
 	if err != nil {
-		log.Println("Transfer() sendTransaction err ", err)
-		return "", err
+		return "", invalid("Transfer", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // Author Authorize an NFT to an exchange
@@ -200,65 +265,16 @@ func (worm *Wormholes) Transfer(wormAddress, to string) (string, error) {
 //	wormAddress: "0x0000000000000000000000000000000000000001",	Authorized worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
 //	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",	Licensee's address
 func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("Author() wormAddress", wormAddress)
-	if err != nil {
-		return "", err
-	}
-	err = tools.CheckAddress("Author() to", to)
-	if err != nil {
-		return "", err
-	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("Author() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:       types2.Author,
-		NFTAddress: wormAddress,
-		Version:    types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("Author failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-
-	fmt.Println(string(tx_data))
+	return worm.AuthorContext(context.Background(), wormAddress, to)
+}
 
-	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("Author() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+// AuthorContext is like Author but uses ctx for the requests sent to the node
+func (worm *Wormholes) AuthorContext(ctx context.Context, wormAddress, to string) (string, error) {
+	c, err := authorCall(wormAddress, to)
 	if err != nil {
-		log.Println("Author signTx err ", err)
-		return "", err
+		return "", invalid("Author", err)
 	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("Author sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // AuthorRevoke Cancel the authorization of an NFT
@@ -267,64 +283,16 @@ func (worm *Wormholes) Author(wormAddress, to string) (string, error) {
 //	wormAddress: "0x0000000000000000000000000000000000000002",	Authorized worm address, the format is a decimal string, when it is SNFT, the length can be less than 42 (including 0x), representing the synthesized SNFT
 //	to:         "0x814920c33b1a037F91a16B126282155c6F92A10F",	Licensee's address
 func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
-	err := tools.CheckHex("AuthorRevoke() wormAddress", wormAddress)
-	if err != nil {
-		return "", err
-	}
-	err = tools.CheckAddress("AuthorRevoke() to", to)
-	if err != nil {
-		return "", err
-	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("AuthorRevoke suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:       types2.AuthorRevoke,
-		NFTAddress: wormAddress,
-		Version:    types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("AuthorRevoke() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.AuthorRevokeContext(context.Background(), wormAddress, to)
+}
 
-	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("AuthorRevoke() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+// AuthorRevokeContext is like AuthorRevoke but uses ctx for the requests sent to the node
+func (worm *Wormholes) AuthorRevokeContext(ctx context.Context, wormAddress, to string) (string, error) {
+	c, err := authorRevokeCall(wormAddress, to)
 	if err != nil {
-		log.Println("AuthorRevoke() signTx err ", err)
-		return "", err
+		return "", invalid("AuthorRevoke", err)
 	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("AuthorRevoke() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // AccountAuthor
@@ -332,59 +300,16 @@ func (worm *Wormholes) AuthorRevoke(wormAddress, to string) (string, error) {
 //	Parameter Description
 //	to:     "0x814920c33b1a037F91a16B126282155c6F92A10F",							Licensee's address
 func (worm *Wormholes) AccountAuthor(to string) (string, error) {
-	err := tools.CheckAddress("AccountAuthor() to", to)
-	if err != nil {
-		return "", err
-	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("AccountAuthor() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.AccountAuthor,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("AccountAuthor() ailed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.AccountAuthorContext(context.Background(), to)
+}
 
-	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("AccountAuthor() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("AccountAuthor() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// AccountAuthorContext is like AccountAuthor but uses ctx for the requests sent to the node
+func (worm *Wormholes) AccountAuthorContext(ctx context.Context, to string) (string, error) {
+	c, err := accountAuthorCall(to)
 	if err != nil {
-		log.Println("AccountAuthor sendTransaction err ", err)
-		return "", err
+		return "", invalid("AccountAuthor", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // AccountAuthorRevoke
@@ -393,59 +318,16 @@ func (worm *Wormholes) AccountAuthor(to string) (string, error) {
 //	Parameter Description
 //	to:     "0x814920c33b1a037F91a16B126282155c6F92A10F",							Licensee's address
 func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
-	err := tools.CheckAddress("AccountAuthorRevoke() to", to)
-	if err != nil {
-		return "", err
-	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("AccountAuthorRevoke() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.AccountAuthorRevoke,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("AccountAuthorRevoke() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.AccountAuthorRevokeContext(context.Background(), to)
+}
 
-	tx := types.NewTransaction(nonce, toAddr, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("AccountAuthorRevoke() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+// AccountAuthorRevokeContext is like AccountAuthorRevoke but uses ctx for the requests sent to the node
+func (worm *Wormholes) AccountAuthorRevokeContext(ctx context.Context, to string) (string, error) {
+	c, err := accountAuthorRevokeCall(to)
 	if err != nil {
-		log.Println("AccountAuthorRevoke() signTx err ", err)
-		return "", err
+		return "", invalid("AccountAuthorRevoke", err)
 	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("AccountAuthorRevoke() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // SNFTToERB
@@ -460,488 +342,130 @@ func (worm *Wormholes) AccountAuthorRevoke(to string) (string, error) {
 //	2: 225000000000000000
 //	3: 300000000000000000
 func (worm *Wormholes) SNFTToERB(wormAddress string) (string, error) {
-	err := tools.CheckHex("SNFTToERB() wormAddress", wormAddress)
-	if err != nil {
-		return "", err
-	}
+	return worm.SNFTToERBContext(context.Background(), wormAddress)
+}
 
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+// SNFTToERBContext is like SNFTToERB but uses ctx for the requests sent to the node
+func (worm *Wormholes) SNFTToERBContext(ctx context.Context, wormAddress string) (string, error) {
+	c, err := snftToERBCall(wormAddress)
 	if err != nil {
-		return "", err
+		return "", invalid("SNFTToERB", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	nonce, err := worm.PendingNonceAt(ctx, account)
+// SNFTPledge
+//	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
+func (worm *Wormholes) SNFTPledge(snftAddress string) (string, error) {
+	return worm.SNFTPledgeContext(context.Background(), snftAddress)
+}
 
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
+// SNFTPledgeContext is like SNFTPledge but uses ctx for the requests sent to the node
+func (worm *Wormholes) SNFTPledgeContext(ctx context.Context, snftAddress string) (string, error) {
+	c, err := snftPledgeCall(snftAddress)
 	if err != nil {
-		log.Println("SNFTToERB() suggestGasPrice err ", err)
-		return "", err
+		return "", invalid("SNFTPledge", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	transaction := types2.Transaction{
-		Type:       types2.SNFTToERB,
-		NFTAddress: wormAddress,
-		Version:    types2.WormHolesVersion,
-	}
+// SNFTRevokesPledge
+//	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
+func (worm *Wormholes) SNFTRevokesPledge(snftaAddress string) (string, error) {
+	return worm.SNFTRevokesPledgeContext(context.Background(), snftaAddress)
+}
 
-	data, err := json.Marshal(transaction)
+// SNFTRevokesPledgeContext is like SNFTRevokesPledge but uses ctx for the requests sent to the node
+func (worm *Wormholes) SNFTRevokesPledgeContext(ctx context.Context, snftaAddress string) (string, error) {
+	c, err := snftRevokesPledgeCall(snftaAddress)
 	if err != nil {
-		log.Println("SNFTToERB() failed to format wormholes data")
-		return "", err
+		return "", invalid("SNFTRevokesPledge", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+// TokenPledge
+//	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
+func (worm *Wormholes) TokenPledge(proxySign []byte, proxyAddress string, value int64) (string, error) {
+	return worm.TokenPledgeContext(context.Background(), proxySign, proxyAddress, value)
+}
 
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("SNFTToERB() networkID err ", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("SNFTToERB() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// TokenPledgeContext is like TokenPledge but uses ctx for the requests sent to the node
+func (worm *Wormholes) TokenPledgeContext(ctx context.Context, proxySign []byte, proxyAddress string, value int64) (string, error) {
+	c, err := tokenPledgeCall(proxySign, proxyAddress, value)
 	if err != nil {
-		log.Println("SNFTToERB() sendTransaction err ", err)
-		return "", err
+		return "", invalid("TokenPledge", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
-// SNFTPledge
-//	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
-func (worm *Wormholes) SNFTPledge(snftAddress string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
+// TokenRevokesPledge
+//	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
+func (worm *Wormholes) TokenRevokesPledge(value int64) (string, error) {
+	return worm.TokenRevokesPledgeContext(context.Background(), value)
+}
+
+// TokenRevokesPledgeContext is like TokenRevokesPledge but uses ctx for the requests sent to the node
+func (worm *Wormholes) TokenRevokesPledgeContext(ctx context.Context, value int64) (string, error) {
+	c, err := tokenRevokesPledgeCall(value)
 	if err != nil {
-		log.Println("TokenPledge() priKeyToAddress err ", err)
-		return "", err
+		return "", invalid("TokenRevokesPledge", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	nonce, err := worm.PendingNonceAt(ctx, account)
+// Open
+//	This transaction can be initiated when a user wants to open an exchange
+//
+//	Parameter Description
+//	feeRate:   10,																		  The exchange rate, the format is an integer type
+//	name:      "wormholes",										 Exchange name, formatted as a string
+//	url:       "www.kang123456.com",		Exchange server address, formatted as a string
+func (worm *Wormholes) Open(feeRate uint32, name, url string) (string, error) {
+	return worm.OpenContext(context.Background(), feeRate, name, url)
+}
 
-	gasLimit := uint64(70000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
+// OpenContext is like Open but uses ctx for the requests sent to the node
+func (worm *Wormholes) OpenContext(ctx context.Context, feeRate uint32, name, url string) (string, error) {
+	c, err := openCall(feeRate, name, url)
 	if err != nil {
-		log.Println("TokenPledge() suggestGasPrice err ", err)
-		return "", err
+		return "", invalid("Open", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	transaction := types2.Transaction{
-		Type:       types2.SNFTPledge,
-		NFTAddress: snftAddress,
-		Version:    types2.WormHolesVersion,
-	}
+// Close
+//	When the user does not want to continue to open an exchange, he can initiate this transaction to close the opened exchange
+func (worm *Wormholes) Close() (string, error) {
+	return worm.CloseContext(context.Background())
+}
 
-	data, err := json.Marshal(transaction)
+// CloseContext is like Close but uses ctx for the requests sent to the node
+func (worm *Wormholes) CloseContext(ctx context.Context) (string, error) {
+	c, err := closeCall()
 	if err != nil {
-		log.Println("TokenPledge() failed to format wormholes data")
-		return "", err
+		return "", invalid("Close", err)
 	}
+	return worm.transact(ctx, c)
+}
 
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+// TransactionNFT
+//	For buying and selling NFTs that have been minted, the transaction originator can be an exchange or a seller
+//
+//	Parameter Description
+//	buyer: { "price":"0xde0b6b3a7640000", "worm_address":"0x0000000000000000000000000000000000000002", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x487", "sig":"0x24355436e991443b8ed3fb83e8c2fa02f8e2bfc0f716c320f836ee7d756e3c712e7e2510b994d1cb7be85d6643233abc81c23929ce7c1c1effd93db261aac5211b" }																				buyer
+//	to:     "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",				Buyer's address
+func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
+	return worm.TransactionNFTContext(context.Background(), buyer, to)
+}
 
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(100000), wei)
-	tx := types.NewTransaction(nonce, account, pledge, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("TokenPledge() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
+// TransactionNFTContext is like TransactionNFT but uses ctx for the requests sent to the node
+func (worm *Wormholes) TransactionNFTContext(ctx context.Context, buyer []byte, to string) (string, error) {
+	c, err := transactionNFTCall(buyer, to)
 	if err != nil {
-		log.Println("TokenPledge() signTx err ", err)
-		return "", err
+		return "", invalid("TransactionNFT", err)
 	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("TokenPledge() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// SNFTRevokesPledge
-//	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
-func (worm *Wormholes) SNFTRevokesPledge(snftaAddress string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("TokenRevokesPledge() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:       types2.SNFTRevokesPledge,
-		NFTAddress: snftaAddress,
-		Version:    types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("TokenRevokesPledge() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(100000), wei)
-
-	tx := types.NewTransaction(nonce, account, pledge, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("TokenRevokesPledge() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// TokenPledge
-//	When a user wants to become a miner, he needs to do an ERB pledge transaction first to pledge the ERB needed to become a miner
-func (worm *Wormholes) TokenPledge(proxySign []byte, proxyAddress string, value int64) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("TokenPledge() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(70000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("TokenPledge() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:         types2.TokenPledge,
-		ProxyAddress: proxyAddress,
-		ProxySign:    string(proxySign),
-		Version:      types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("TokenPledge() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(value), wei)
-	tx := types.NewTransaction(nonce, account, pledge, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("TokenPledge() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("TokenPledge() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("TokenPledge() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// TokenRevokesPledge
-//	When the user does not want to be a miner, or no longer wants to pledge so much ERB, he can do ERB to revoke the pledge
-func (worm *Wormholes) TokenRevokesPledge(value int64) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("TokenRevokesPledge() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.TokenRevokesPledge,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("TokenRevokesPledge() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	pledge := new(big.Int).Mul(big.NewInt(value), wei)
-
-	tx := types.NewTransaction(nonce, account, pledge, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("TokenRevokesPledge() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("TokenRevokesPledge() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// Open
-//	This transaction can be initiated when a user wants to open an exchange
-//
-//	Parameter Description
-//	feeRate:   10,																		  The exchange rate, the format is an integer type
-//	name:      "wormholes",										 Exchange name, formatted as a string
-//	url:       "www.kang123456.com",		Exchange server address, formatted as a string
-func (worm *Wormholes) Open(feeRate uint32, name, url string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("Open() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(60000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("Open() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.Open,
-		FeeRate: feeRate,
-		Name:    name,
-		Url:     url,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("Open() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	wei, _ := new(big.Int).SetString("1000000000000000000", 10)
-	amount := new(big.Int).Mul(big.NewInt(100), wei)
-
-	tx := types.NewTransaction(nonce, account, amount, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("open() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("open() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("open() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// Close
-//	When the user does not want to continue to open an exchange, he can initiate this transaction to close the opened exchange
-func (worm *Wormholes) Close() (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("close() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(60000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("close() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.Close,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("close() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("close networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("close() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("close() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
-}
-
-// TransactionNFT
-//	For buying and selling NFTs that have been minted, the transaction originator can be an exchange or a seller
-//
-//	Parameter Description
-//	buyer: { "price":"0xde0b6b3a7640000", "worm_address":"0x0000000000000000000000000000000000000002", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x487", "sig":"0x24355436e991443b8ed3fb83e8c2fa02f8e2bfc0f716c320f836ee7d756e3c712e7e2510b994d1cb7be85d6643233abc81c23929ce7c1c1effd93db261aac5211b" }																				buyer
-//	to:     "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",				Buyer's address
-func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
-	err := tools.CheckAddress("TransactionNFT() to", to)
-	if err != nil {
-		return "", err
-	}
-
-	var buyers types2.Buyer
-	err = json.Unmarshal(buyer, &buyers)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("TransactionNFT() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	toAddr := common.HexToAddress(to)
-
-	gasLimit := uint64(100000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("TransactionNFT() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	//msg := buyer.Amount + buyer.NFTAddress + buyer.Exchanger + buyer.BlockNumber
-	//
-	//sig, err := tools.Sign([]byte(msg), wormholes.Buyer.PriKey)
-	//if err != nil {
-	//	return "", err
-	//}
-	//buyer.Sig = hexutil.Encode(sig)
-
-	transaction := types2.Transaction{
-		Type:    types2.TransactionNFT,
-		Buyer:   &buyers,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("TransactionNFT() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
-
-	value, _ := hexutil.DecodeBig(buyers.Amount)
-	fmt.Println(value)
-	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("TransactionNFT() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("TransactionNFT() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("TransactionNFT sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // BuyerInitiatingTransaction
@@ -950,66 +474,16 @@ func (worm *Wormholes) TransactionNFT(buyer []byte, to string) (string, error) {
 //	Parameter Description
 //	seller1: { "price":"0x38D7EA4C68000", "worm_address":"0x0000000000000000000000000000000000000003", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x65d", "sig":"0x94e88fb5686551dfc3006c608423983a248df8502cbbcaeb2c3352f267a25e531d5fc745bea5f7f564b7399fb70d87026bbf9952f1403e9d4dae4aa14b091cff1c" }
 func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error) {
-	var seller1s types2.Seller1
-	err := json.Unmarshal(seller1, &seller1s)
-	if err != nil {
-		return "", xerrors.New("the formate of seller1 is wrong")
-	}
-
-	err = tools.CheckHex("seller1s.BlockNumber", seller1s.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("BuyerInitiatingTransaction() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(100000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("BuyerInitiatingTransaction() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.BuyerInitiatingTransaction,
-		Seller1: &seller1s,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("BuyerInitiatingTransaction() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.BuyerInitiatingTransactionContext(context.Background(), seller1)
+}
 
-	value, _ := hexutil.DecodeBig(seller1s.Amount)
-	tx := types.NewTransaction(nonce, account, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("BuyerInitiatingTransaction networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("BuyerInitiatingTransaction signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// BuyerInitiatingTransactionContext is like BuyerInitiatingTransaction but uses ctx for the requests sent to the node
+func (worm *Wormholes) BuyerInitiatingTransactionContext(ctx context.Context, seller1 []byte) (string, error) {
+	c, err := buyerInitiatingTransactionCall(seller1)
 	if err != nil {
-		log.Println("BuyerInitiatingTransaction sendTransaction err ", err)
-		return "", err
+		return "", invalid("BuyerInitiatingTransaction", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // FoundryTradeBuyer
@@ -1018,72 +492,16 @@ func (worm *Wormholes) BuyerInitiatingTransaction(seller1 []byte) (string, error
 //	Parameter Description
 //	seller2: { "price":"0x38D7EA4C68000", "royalty":"0xa", "meta_url":"/ipfs/qqqqqqqqqq", "exclusive_flag":"0", "exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4", "block_number":"0x703", "sig":"0xb08cf8b2f2d4b2635a85d1c7a816f01c24ac2a90ab49bdbe0e52e0a8f07eea5521eb80554df2c403423bdf49f412a7811b10a16005832a1bc171f5dfd3c983121c" }
 func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
-	var seller2s types2.Seller2
-	err := json.Unmarshal([]byte(seller2), &seller2s)
-	if err != nil {
-		return "", xerrors.New("the formate of seller2 is wrong")
-	}
-
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
-	if err != nil {
-		return "", err
-	}
-
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("FoundryTradeBuyer() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(101000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("FoundryTradeBuyer() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.FoundryTradeBuyer,
-		Seller2: &seller2s,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("FoundryTradeBuyer() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.FoundryTradeBuyerContext(context.Background(), seller2)
+}
 
-	value, _ := hexutil.DecodeBig(seller2s.Amount)
-	tx := types.NewTransaction(nonce, account, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
+// FoundryTradeBuyerContext is like FoundryTradeBuyer but uses ctx for the requests sent to the node
+func (worm *Wormholes) FoundryTradeBuyerContext(ctx context.Context, seller2 []byte) (string, error) {
+	c, err := foundryTradeBuyerCall(seller2)
 	if err != nil {
-		log.Println("FoundryTradeBuyer() failed to format wormholes dataNetworkID err=", err)
-		return "", err
+		return "", invalid("FoundryTradeBuyer", err)
 	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("FoundryTradeBuyer() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("FoundryTradeBuyer() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // FoundryExchange
@@ -1094,97 +512,16 @@ func (worm *Wormholes) FoundryTradeBuyer(seller2 []byte) (string, error) {
 //	seller2: {"price":"0x38D7EA4C68000","royalty":"0xa","meta_url":"/ipfs/qqqqqqqqqq","exclusive_flag":"0","exchanger":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","block_number":"0x7be","sig":"0x84c0c293298557e38fa5064a6fb3b9e6930fa46b234fcd0a923cd677369f5aad3f014a164b21077f713e25b4e986673f614f6ce824561fbda2b4e67e018fac6f1b"}
 //	to:      "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",  Buyer's address
 func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string, error) {
-	err := tools.CheckAddress("to", to)
-	if err != nil {
-		return "", err
-	}
-
-	var buyers types2.Buyer
-	err = json.Unmarshal(buyer, &buyers)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	var seller2s types2.Seller2
-	err = json.Unmarshal(seller2, &seller2s)
-	if err != nil {
-		return "", xerrors.New("the formate of seller2 is wrong")
-	}
-
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
-	if err != nil {
-		return "", err
-	}
-
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	if buyers.Amount < seller2s.Amount {
-		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
-	}
-	if seller2s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
-	}
-
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("FoundryExchange() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(140000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("FoundryExchange() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.FoundryExchange,
-		Buyer:   &buyers,
-		Seller2: &seller2s,
-		Version: types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("FoundryExchange() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.FoundryExchangeContext(context.Background(), buyer, seller2, to)
+}
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
-	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("FoundryExchange() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("FoundryExchange() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// FoundryExchangeContext is like FoundryExchange but uses ctx for the requests sent to the node
+func (worm *Wormholes) FoundryExchangeContext(ctx context.Context, buyer, seller2 []byte, to string) (string, error) {
+	c, err := foundryExchangeCall(buyer, seller2, to)
 	if err != nil {
-		log.Println("FoundryExchange() sendTransaction err ", err)
-		return "", err
+		return "", invalid("FoundryExchange", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // NftExchangeMatch
@@ -1195,96 +532,16 @@ func (worm *Wormholes) FoundryExchange(buyer, seller2 []byte, to string) (string
 //	{"exchanger_owner":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","to":"0xEaE404DCa7c22A15A59f63002Df54BBb8D90c5FB","block_number":"0x92b","sig":"0x972099c287a8da54bb13e7134fcd7edcf96122f1dc949ab987961072011e57662ccb9482ed3738fcdefa613a4d7f58b02fffdf4702943e48bc93af3be7af34191c"}
 //	to            "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",	Buyer's address
 func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to string) (string, error) {
-	err := tools.CheckAddress("NftExchangeMatch() to", to)
-	if err != nil {
-		return "", err
-	}
-	toAddr := common.HexToAddress(to)
-
-	var buyers types2.Buyer
-	err = json.Unmarshal(buyer, &buyers)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	var sellers types2.Seller1
-	err = json.Unmarshal(seller, &sellers)
-	if err != nil {
-		return "", xerrors.New("the formate of sellers is wrong")
-	}
-
-	err = tools.CheckHex("sellers.BlockNumber", sellers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	var exchangeAuths types2.ExchangerAuth
-	err = json.Unmarshal(exchangerAuth, &exchangeAuths)
-	if err != nil {
-		return "", xerrors.New("the formate of exchangerAuth is wrong")
-	}
-
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangeAuths.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("NftExchangeMatch() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(140000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("NftExchangeMatch() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:          types2.NftExchangeMatch,
-		Buyer:         &buyers,
-		Seller1:       &sellers,
-		ExchangerAuth: &exchangeAuths,
-		Version:       types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("NftExchangeMatch() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.NftExchangeMatchContext(context.Background(), buyer, seller, exchangerAuth, to)
+}
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
-	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
+// NftExchangeMatchContext is like NftExchangeMatch but uses ctx for the requests sent to the node
+func (worm *Wormholes) NftExchangeMatchContext(ctx context.Context, buyer, seller, exchangerAuth []byte, to string) (string, error) {
+	c, err := nftExchangeMatchCall(buyer, seller, exchangerAuth, to)
 	if err != nil {
-		log.Println("NftExchangeMatch() networkID err=", err)
-		return "", err
+		return "", invalid("NftExchangeMatch", err)
 	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("NftExchangeMatch signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("NftExchangeMatch sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // FoundryExchangeInitiated
@@ -1296,120 +553,16 @@ func (worm *Wormholes) NftExchangeMatch(buyer, seller, exchangerAuth []byte, to
 //	exchangerAuth:	{"exchanger_owner":"0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4","to":"0xEaE404DCa7c22A15A59f63002Df54BBb8D90c5FB","block_number":"0x26","sig":"0x8c1706b407f50ed5cec8a392eac5f66f0338e9cf4eb71a465dc264ac7e315d2068f6061dfec02ee6b6f7f1150d1594c829436c36bc49c806ee5f5b4ad04e43631c"}
 //	to:            "0x5051B76579BC966A9480dd6E72B39A4C89c1154C",	Buyer's address
 func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []byte, to string) (string, error) {
-	err := tools.CheckAddress("FoundryExchangeInitiated() to", to)
-	if err != nil {
-		return "", err
-	}
-
-	var buyers types2.Buyer
-	err = json.Unmarshal(buyer, &buyers)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	var seller2s types2.Seller2
-	err = json.Unmarshal(seller2, &seller2s)
-	if err != nil {
-		return "", xerrors.New("the formate of seller2 is wrong")
-	}
-
-	err = tools.CheckFlag("seller2s.ExclusiveFlag", seller2s.ExclusiveFlag)
-	if err != nil {
-		return "", err
-	}
-
-	err = tools.CheckHex("seller2s.BlockNumber", seller2s.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	//sellerMsg := seller2s.Amount +
-	//	seller2s.Royalty +
-	//	seller2s.MetaURL +
-	//	seller2s.ExclusiveFlag +
-	//	seller2s.Exchanger +
-	//	seller2s.BlockNumber
-	//
-	//addr, _ := tools.RecoverAddress(sellerMsg, seller2s.Sig)
-	//fmt.Println("---------------seller", addr.String())
-
-	if buyers.Amount < seller2s.Amount {
-		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
-	}
-	if seller2s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
-	}
-
-	var exchangerAuths types2.ExchangerAuth
-	err = json.Unmarshal([]byte(exchangerAuth), &exchangerAuths)
-	if err != nil {
-		return "", xerrors.New("the formate of exchangerAuthor is wrong")
-	}
-
-	err = tools.CheckHex("exchangeAuths.BlockNumber", exchangerAuths.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("FoundryExchangeInitiated() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	ctx := context.Background()
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(170000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("FoundryExchangeInitiated() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:          types2.FoundryExchangeInitiated,
-		Buyer:         &buyers,
-		Seller2:       &seller2s,
-		ExchangerAuth: &exchangerAuths,
-		Version:       types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("FoundryExchangeInitiated() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.FoundryExchangeInitiatedContext(context.Background(), buyer, seller2, exchangerAuth, to)
+}
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
-	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("FoundryExchangeInitiated() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("FoundryExchangeInitiated() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// FoundryExchangeInitiatedContext is like FoundryExchangeInitiated but uses ctx for the requests sent to the node
+func (worm *Wormholes) FoundryExchangeInitiatedContext(ctx context.Context, buyer, seller2, exchangerAuth []byte, to string) (string, error) {
+	c, err := foundryExchangeInitiatedCall(buyer, seller2, exchangerAuth, to)
 	if err != nil {
-		log.Println("FoundryExchangeInitiated() sendTransaction err ", err)
-		return "", err
+		return "", invalid("FoundryExchangeInitiated", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // NFTDoesNotAuthorizeExchanges
@@ -1420,91 +573,16 @@ func (worm *Wormholes) FoundryExchangeInitiated(buyer, seller2, exchangerAuth []
 //	seller1: {"price":"0xde0b6b3a7640000","worm_address":"0x0000000000000000000000000000000000000002","exchanger":"0x5051B76579BC966A9480dd6E72B39A4C89c1154C","block_number":"0x113","sig":"0x1c8559524220b49e6b9548be405331228d8f26ced8ce12e81b672443fe28067327eef62ce2b3826e2e9ec10f8b2cf5d8a2b2519a0e95f288ea3f098fdea6ab6b1c"}
 //	to:      "0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4",		Buyer's address
 func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to string) (string, error) {
-	err := tools.CheckAddress("FtDoesNotAuthorizeExchanges() to", to)
-	if err != nil {
-		return "", err
-	}
-	var buyers types2.Buyer
-	err = json.Unmarshal(buyer, &buyers)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("buyers.BlockNumber", buyers.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	var seller1s types2.Seller1
-	err = json.Unmarshal(seller1, &seller1s)
-	if err != nil {
-		return "", xerrors.New("the formate of buyer is wrong")
-	}
-
-	err = tools.CheckHex("seller1s.BlockNumber", seller1s.BlockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	if buyers.Amount < seller1s.Amount {
-		return "", xerrors.New("buyer`s amount must be greater then seller`s amount")
-	}
-	if seller1s.Exchanger != buyers.Exchanger {
-		return "", xerrors.New("buyer`s exchanger and seller`s exchanger and transaction`s exchanger aren`t same")
-	}
-
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	toAddr := common.HexToAddress(to)
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(130000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.FtDoesNotAuthorizeExchanges,
-		Buyer:   &buyers,
-		Seller1: &seller1s,
-		Version: types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.NFTDoesNotAuthorizeExchangesContext(context.Background(), buyer, seller1, to)
+}
 
-	value, _ := hexutil.DecodeBig(buyers.Amount)
-	tx := types.NewTransaction(nonce, toAddr, value, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// NFTDoesNotAuthorizeExchangesContext is like NFTDoesNotAuthorizeExchanges but uses ctx for the requests sent to the node
+func (worm *Wormholes) NFTDoesNotAuthorizeExchangesContext(ctx context.Context, buyer, seller1 []byte, to string) (string, error) {
+	c, err := nftDoesNotAuthorizeExchangesCall(buyer, seller1, to)
 	if err != nil {
-		log.Println("FtDoesNotAuthorizeExchanges() sendTransaction err ", err)
-		return "", err
+		return "", invalid("NFTDoesNotAuthorizeExchanges", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // AdditionalPledgeAmount
@@ -1513,54 +591,16 @@ func (worm *Wormholes) NFTDoesNotAuthorizeExchanges(buyer, seller1 []byte, to st
 //	Parameter Description
 //	value:  100,		Append amount, format is hex string
 func (worm *Wormholes) AdditionalPledgeAmount(value int64) (string, error) {
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("AdditionalPledgeAmount() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(55000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("AdditionalPledgeAmount() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.AdditionalPledgeAmount,
-		Version: types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("AdditionalPledgeAmount() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.AdditionalPledgeAmountContext(context.Background(), value)
+}
 
-	additional := big.NewInt(value)
-	tx := types.NewTransaction(nonce, account, additional, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("AdditionalPledgeAmount() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("AdditionalPledgeAmount() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// AdditionalPledgeAmountContext is like AdditionalPledgeAmount but uses ctx for the requests sent to the node
+func (worm *Wormholes) AdditionalPledgeAmountContext(ctx context.Context, value int64) (string, error) {
+	c, err := additionalPledgeAmountCall(value)
 	if err != nil {
-		log.Println("AdditionalPledgeAmount() sendTransaction err ", err)
-		return "", err
+		return "", invalid("AdditionalPledgeAmount", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // RevokesPledgeAmount
@@ -1569,54 +609,16 @@ func (worm *Wormholes) AdditionalPledgeAmount(value int64) (string, error) {
 //	Parameter Description
 //	value:  100,		Amount to decrease, format is hexadecimal string
 func (worm *Wormholes) RevokesPledgeAmount(value int64) (string, error) {
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("RevokesPledgeAmount() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	ctx := context.Background()
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(55000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("RevokesPledgeAmount() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.RevokesPledgeAmount,
-		Version: types2.WormHolesVersion,
-	}
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("RevokesPledgeAmount() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.RevokesPledgeAmountContext(context.Background(), value)
+}
 
-	revokes := big.NewInt(value)
-	tx := types.NewTransaction(nonce, account, revokes, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("RevokesPledgeAmount() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("RevokesPledgeAmount() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// RevokesPledgeAmountContext is like RevokesPledgeAmount but uses ctx for the requests sent to the node
+func (worm *Wormholes) RevokesPledgeAmountContext(ctx context.Context, value int64) (string, error) {
+	c, err := revokesPledgeAmountCall(value)
 	if err != nil {
-		log.Println("RevokesPledgeAmount() sendTransaction err ", err)
-		return "", err
+		return "", invalid("RevokesPledgeAmount", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // VoteOfficialNFT
@@ -1629,63 +631,16 @@ func (worm *Wormholes) RevokesPledgeAmount(value int64) (string, error) {
 //	royalty:    20,																			Royalty, formatted as an integer
 //	creator:    "0xab7624f47fd7dadb6b8e255d06a2f10af55990fe",	creator, format is a hex string
 func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, royalty uint32, creator string) (string, error) {
-	err := tools.CheckAddress("VoteOfficialNFT() creator", creator)
-	if err != nil {
-		return "", err
-	}
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("VoteOfficialNFT() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(60000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("VoteOfficialNFT() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:       types2.VoteOfficialNFT,
-		Dir:        dir,
-		StartIndex: startIndex,
-		Number:     number,
-		Royalty:    royalty,
-		Creator:    creator,
-		Version:    types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("VoteOfficialNFT() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.VoteOfficialNFTContext(context.Background(), dir, startIndex, number, royalty, creator)
+}
 
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("VoteOfficialNFT() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("VoteOfficialNFT() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// VoteOfficialNFTContext is like VoteOfficialNFT but uses ctx for the requests sent to the node
+func (worm *Wormholes) VoteOfficialNFTContext(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string) (string, error) {
+	c, err := voteOfficialNFTCall(dir, startIndex, number, royalty, creator)
 	if err != nil {
-		log.Println("VoteOfficialNFT() sendTransaction err ", err)
-		return "", err
+		return "", invalid("VoteOfficialNFT", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // VoteOfficialNFTByApprovedExchanger
@@ -1699,124 +654,31 @@ func (worm *Wormholes) VoteOfficialNFT(dir, startIndex string, number uint64, ro
 //  exchanger:	{"exchanger_owner":"0x83c43f6F7bB4d8E429b21FF303a16b4c99A59b05","to":"0xB685EB3226d5F0D549607D2cC18672b756fd090c","block_number":"0x0","sig":"0xae18a165e51e322d04d2862b6e2760d0493b58870f9afe3c6d15b6e44145c293075662043611501c89d3e4b299a21fe1f8581def86cce4dd43b20c47960ac2481c"}
 //	creator:    "0xab7624f47fd7dadb6b8e255d06a2f10af55990fe",	creator, format is a hex string
 func (worm *Wormholes) VoteOfficialNFTByApprovedExchanger(dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error) {
-	err := tools.CheckAddress("VoteOfficialNFTByApprovedExchanger() creator", creator)
-	if err != nil {
-		return "", err
-	}
-
-	var exchangeAuths types2.ExchangerAuth
-	err = json.Unmarshal(exchangerAuth, &exchangeAuths)
-	if err != nil {
-		return "", xerrors.New("the formate of exchangerAuth is wrong")
-	}
-
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(60000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:          types2.VoteOfficialNFTByApprovedExchanger,
-		Dir:           dir,
-		StartIndex:    startIndex,
-		Number:        number,
-		Royalty:       royalty,
-		Creator:       creator,
-		ExchangerAuth: &exchangeAuths,
-		Version:       types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.VoteOfficialNFTByApprovedExchangerContext(context.Background(), dir, startIndex, number, royalty, creator, exchangerAuth)
+}
 
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// VoteOfficialNFTByApprovedExchangerContext is like VoteOfficialNFTByApprovedExchanger but uses ctx for the requests sent to the node
+func (worm *Wormholes) VoteOfficialNFTByApprovedExchangerContext(ctx context.Context, dir, startIndex string, number uint64, royalty uint32, creator string, exchangerAuth []byte) (string, error) {
+	c, err := voteOfficialNFTByApprovedExchangerCall(dir, startIndex, number, royalty, creator, exchangerAuth)
 	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() sendTransaction err ", err)
-		return "", err
+		return "", invalid("VoteOfficialNFTByApprovedExchanger", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 // UnforzenAccount
 //	change revenue model
 func (worm *Wormholes) UnforzenAccount() (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(50000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("ASuggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:    types2.UnforzenAccount,
-		Version: types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.UnforzenAccountContext(context.Background())
+}
 
-	tx := types.NewTransaction(nonce, account, nil, gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
+// UnforzenAccountContext is like UnforzenAccount but uses ctx for the requests sent to the node
+func (worm *Wormholes) UnforzenAccountContext(ctx context.Context) (string, error) {
+	c, err := unforzenAccountCall()
 	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() networkID err=", err)
-		return "", err
+		return "", invalid("UnforzenAccount", err)
 	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
-	if err != nil {
-		log.Println("VoteOfficialNFTByApprovedExchanger() sendTransaction err ", err)
-		return "", err
-	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 //AccountDelegate
@@ -1824,56 +686,17 @@ func (worm *Wormholes) UnforzenAccount() (string, error) {
 // Parameter Description
 // proxyAddress:		0xe61e5Bbe724B8F449B5C7BB4a09F99A057253eB4
 func (worm *Wormholes) AccountDelegate(proxySign []byte, proxyAddress string) (string, error) {
-	ctx := context.Background()
-	account, fromKey, err := tools.PriKeyToAddress(worm.priKey)
-	if err != nil {
-		log.Println("AccountDelegate() priKeyToAddress err ", err)
-		return "", err
-	}
-
-	nonce, err := worm.PendingNonceAt(ctx, account)
-
-	gasLimit := uint64(70000)
-	gasPrice, err := worm.SuggestGasPrice(ctx)
-	if err != nil {
-		log.Println("AccountDelegate() suggestGasPrice err ", err)
-		return "", err
-	}
-
-	transaction := types2.Transaction{
-		Type:         types2.AccountDelegate,
-		ProxyAddress: proxyAddress,
-		ProxySign:    string(proxySign),
-		Version:      types2.WormHolesVersion,
-	}
-
-	data, err := json.Marshal(transaction)
-	if err != nil {
-		log.Println("AccountDelegate() failed to format wormholes data")
-		return "", err
-	}
-
-	tx_data := append([]byte("wormholes:"), data...)
-	fmt.Println(string(tx_data))
+	return worm.AccountDelegateContext(context.Background(), proxySign, proxyAddress)
+}
 
-	tx := types.NewTransaction(nonce, account, big.NewInt(0), gasLimit, gasPrice, tx_data)
-	chainID, err := worm.NetworkID(ctx)
-	if err != nil {
-		log.Println("AccountDelegate() networkID err=", err)
-		return "", err
-	}
-	log.Println("chainID=", chainID)
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), fromKey)
-	if err != nil {
-		log.Println("AccountDelegate() signTx err ", err)
-		return "", err
-	}
-	err = worm.SendTransaction(ctx, signedTx)
+// AccountDelegateContext is like AccountDelegate but uses ctx for the requests sent to the node
+func (worm *Wormholes) AccountDelegateContext(ctx context.Context, proxySign []byte, proxyAddress string) (string, error) {
+	c, err := accountDelegateCall(proxySign, proxyAddress)
 	if err != nil {
-		log.Println("AccountSign() sendTransaction err ", err)
-		return "", err
+		return "", invalid("AccountDelegate", err)
 	}
-	return strings.ToLower(signedTx.Hash().String()), nil
+	return worm.transact(ctx, c)
 }
 
 var _ APIs = &Wormholes{}
+var _ ContextAPIs = &Wormholes{}
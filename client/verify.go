@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
+)
+
+// DefaultExpiryMargin is the default number of blocks the orders of a trade must
+// remain valid after the block the trade is expected in
+const DefaultExpiryMargin = 0
+
+// verifyOrders checks the signatures of the orders carried by c when it is sent
+// from the account from. The trades are sent to the buyer, the exchanger
+// authorizations are used by the exchanger they are given to and the seller
+// orders are signed by the seller named by the buyer.
+func verifyOrders(c *txCall, from common.Address) error {
+	payload := c.payload
+	if payload == nil {
+		return nil
+	}
+	if payload.Buyer != nil {
+		_, err := payload.Buyer.Verify(c.recipient(from))
+		if err != nil {
+			return err
+		}
+	}
+	if payload.Seller1 != nil {
+		_, err := payload.Seller1.Verify(common.Address{})
+		if err != nil {
+			return err
+		}
+	}
+	if payload.Seller2 != nil {
+		_, err := payload.Seller2.Verify(common.Address{})
+		if err != nil {
+			return err
+		}
+	}
+	if payload.ExchangerAuth != nil {
+		_, err := payload.ExchangerAuth.Verify()
+		if err != nil {
+			return err
+		}
+		if common.HexToAddress(payload.ExchangerAuth.To) != from {
+			return xerrors.Errorf("the exchanger authorization is given to %s, not to the sender %s", payload.ExchangerAuth.To, from.Hex())
+		}
+	}
+	return types2.MatchOrders(payload, 0)
+}
+
+// hasOrders reports whether the wormholes transaction carries signed orders
+func hasOrders(payload *types2.Transaction) bool {
+	return payload != nil &&
+		(payload.Buyer != nil || payload.Seller1 != nil || payload.Seller2 != nil || payload.ExchangerAuth != nil)
+}
+
+// checkExpiry rejects the orders of c which expire before the next block plus the
+// expiry margin, unless the expiry check is disabled
+func (worm *Wormholes) checkExpiry(ctx context.Context, c *txCall) error {
+	if !worm.expiryCheck || !hasOrders(c.payload) {
+		return nil
+	}
+	head, err := worm.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	return worm.checkOrdersExpiry(c, head)
+}
+
+// checkOrdersExpiry rejects the orders of c which are not valid in the block after
+// head plus the expiry margin
+func (worm *Wormholes) checkOrdersExpiry(c *txCall, head uint64) error {
+	if !hasOrders(c.payload) {
+		return nil
+	}
+	err := types2.CheckExpiry(c.payload, head+1+worm.expiryMargin)
+	if err != nil {
+		return invalid(c.name, err)
+	}
+	return nil
+}
+
+// Deadline returns the block number blocks after the current block, as the hex string
+// expected for the blockNumber of the Wallet signing functions. The orders are valid
+// before that block, so they can be traded in the next blocks-1 blocks.
+//
+//	deadline, err := worm.Deadline(ctx, 100)
+//	buyer, err := worm.SignBuyer(amount, nftAddress, exchanger, deadline, seller)
+func (worm *Wormholes) Deadline(ctx context.Context, blocks uint64) (string, error) {
+	head, err := worm.BlockNumber(ctx)
+	if err != nil {
+		return "", opError("Deadline", nil, err)
+	}
+	return hexutil.EncodeUint64(head + blocks), nil
+}
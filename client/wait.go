@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DefaultPollInterval is the interval at which receipts are polled, the new heads
+// subscribed on websocket and IPC connections wake the polling up earlier
+const DefaultPollInterval = 2 * time.Second
+
+// WaitOptions controls how long WaitMined waits for a transaction
+type WaitOptions struct {
+	// Confirmations is the number of blocks which must be mined on top of the
+	// block of the transaction, 0 returns as soon as the transaction is mined
+	Confirmations uint64
+	// Timeout limits the whole wait, 0 waits until the context is done
+	Timeout time.Duration
+	// PollInterval is the interval of the receipt polling, DefaultPollInterval if 0
+	PollInterval time.Duration
+}
+
+// TxResult is the outcome of a mined transaction
+type TxResult struct {
+	Hash          common.Hash
+	Status        uint64 // types.ReceiptStatusSuccessful or types.ReceiptStatusFailed
+	GasUsed       uint64
+	BlockNumber   uint64
+	BlockHash     common.Hash
+	Confirmations uint64 // number of blocks mined on top of BlockNumber
+	Receipt       *types.Receipt
+}
+
+// Successful reports whether the transaction was executed successfully
+func (r *TxResult) Successful() bool {
+	return r.Status == types.ReceiptStatusSuccessful
+}
+
+// SendAndWait sends a transaction with one of the APIs methods and waits until it is mined
+//
+//	result, err := worm.SendAndWait(ctx, client.WaitOptions{Confirmations: 2}, func() (string, error) {
+//		return worm.MintContext(ctx, 10, "/ipfs/ddfd90be9408b4", "")
+//	})
+func (worm *Wormholes) SendAndWait(ctx context.Context, opts WaitOptions, send func() (string, error)) (*TxResult, error) {
+	hash, err := send()
+	if err != nil {
+		return nil, err
+	}
+	return worm.WaitMined(ctx, hash, opts)
+}
+
+// WaitMined waits until the transaction hash, as returned by the APIs methods, is mined
+// and confirmed by opts.Confirmations blocks.
+// The receipt is polled, on a websocket or IPC connection also at each new head.
+// A reverted transaction is not an error, the status of the result is types.ReceiptStatusFailed.
+func (worm *Wormholes) WaitMined(ctx context.Context, hash string, opts WaitOptions) (*TxResult, error) {
+	if worm.c == nil {
+		return nil, rpcError("WaitMined", worm.connError())
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+
+	// the new heads only wake the wait up early: the subscription is resubscribed in the
+	// background after a connection loss, the blocks mined meanwhile are found by polling
+	heads := make(chan *types.Header, 16)
+	var subErr <-chan error
+	sub, err := worm.SubscribeNewHead(ctx, heads)
+	if err == nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := worm.confirmedResult(ctx, hash, opts.Confirmations)
+		if err != nil || result != nil {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, opError("WaitMined", nil, ctx.Err())
+		case <-heads:
+		case <-ticker.C:
+		case <-subErr:
+			// the subscription is gone, continue by polling
+			subErr = nil
+		}
+	}
+}
+
+// confirmedResult returns the result of the transaction once it is confirmed, or nil if it is not yet
+func (worm *Wormholes) confirmedResult(ctx context.Context, hash string, confirmations uint64) (*TxResult, error) {
+	receipt, err := worm.TransactionReceipt(ctx, hash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	number := receipt.BlockNumber.Uint64()
+	head, err := worm.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if head < number+confirmations {
+		return nil, nil
+	}
+	return &TxResult{
+		Hash:          receipt.TxHash,
+		Status:        receipt.Status,
+		GasUsed:       receipt.GasUsed,
+		BlockNumber:   number,
+		BlockHash:     receipt.BlockHash,
+		Confirmations: head - number,
+		Receipt:       receipt,
+	}, nil
+}
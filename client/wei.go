@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// The methods below take their value in wei, so amounts which are not whole ERB
+// can be sent. The unit package converts amounts such as "1.25 ERB" to wei:
+//
+//	value, err := unit.Parse("1.25 ERB")
+//	hash, err := worm.NormalTransactionWei(to, value, "")
+
+// NormalTransactionWei is like NormalTransaction but sends value wei
+func (worm *Wormholes) NormalTransactionWei(to string, value *big.Int, data string) (string, error) {
+	return worm.NormalTransactionWeiContext(context.Background(), to, value, data)
+}
+
+// NormalTransactionWeiContext is like NormalTransactionWei but uses ctx for the requests sent to the node
+func (worm *Wormholes) NormalTransactionWeiContext(ctx context.Context, to string, value *big.Int, data string) (string, error) {
+	c, err := normalTransactionWeiCall(to, value, data)
+	if err != nil {
+		return "", invalid("NormalTransaction", err)
+	}
+	return worm.transact(ctx, c)
+}
+
+// TokenPledgeWei is like TokenPledge but pledges value wei
+func (worm *Wormholes) TokenPledgeWei(proxySign []byte, proxyAddress string, value *big.Int) (string, error) {
+	return worm.TokenPledgeWeiContext(context.Background(), proxySign, proxyAddress, value)
+}
+
+// TokenPledgeWeiContext is like TokenPledgeWei but uses ctx for the requests sent to the node
+func (worm *Wormholes) TokenPledgeWeiContext(ctx context.Context, proxySign []byte, proxyAddress string, value *big.Int) (string, error) {
+	c, err := tokenPledgeWeiCall(proxySign, proxyAddress, value)
+	if err != nil {
+		return "", invalid("TokenPledge", err)
+	}
+	return worm.transact(ctx, c)
+}
+
+// TokenRevokesPledgeWei is like TokenRevokesPledge but revokes value wei
+func (worm *Wormholes) TokenRevokesPledgeWei(value *big.Int) (string, error) {
+	return worm.TokenRevokesPledgeWeiContext(context.Background(), value)
+}
+
+// TokenRevokesPledgeWeiContext is like TokenRevokesPledgeWei but uses ctx for the requests sent to the node
+func (worm *Wormholes) TokenRevokesPledgeWeiContext(ctx context.Context, value *big.Int) (string, error) {
+	c, err := tokenRevokesPledgeWeiCall(value)
+	if err != nil {
+		return "", invalid("TokenRevokesPledge", err)
+	}
+	return worm.transact(ctx, c)
+}
+
+// AdditionalPledgeAmountWei is like AdditionalPledgeAmount with a value of arbitrary size
+func (worm *Wormholes) AdditionalPledgeAmountWei(value *big.Int) (string, error) {
+	return worm.AdditionalPledgeAmountWeiContext(context.Background(), value)
+}
+
+// AdditionalPledgeAmountWeiContext is like AdditionalPledgeAmountWei but uses ctx for the requests sent to the node
+func (worm *Wormholes) AdditionalPledgeAmountWeiContext(ctx context.Context, value *big.Int) (string, error) {
+	c, err := additionalPledgeAmountWeiCall(value)
+	if err != nil {
+		return "", invalid("AdditionalPledgeAmount", err)
+	}
+	return worm.transact(ctx, c)
+}
+
+// RevokesPledgeAmountWei is like RevokesPledgeAmount with a value of arbitrary size
+func (worm *Wormholes) RevokesPledgeAmountWei(value *big.Int) (string, error) {
+	return worm.RevokesPledgeAmountWeiContext(context.Background(), value)
+}
+
+// RevokesPledgeAmountWeiContext is like RevokesPledgeAmountWei but uses ctx for the requests sent to the node
+func (worm *Wormholes) RevokesPledgeAmountWeiContext(ctx context.Context, value *big.Int) (string, error) {
+	c, err := revokesPledgeAmountWeiCall(value)
+	if err != nil {
+		return "", invalid("RevokesPledgeAmount", err)
+	}
+	return worm.transact(ctx, c)
+}
+
+// NormalTransactionWei builds an unsigned transfer of value wei, see Wormholes.NormalTransactionWei
+func (b *TxBuilder) NormalTransactionWei(to string, value *big.Int, data string) (*types.Transaction, error) {
+	return b.build(normalTransactionWeiCall(to, value, data))
+}
+
+// TokenPledgeWei builds an unsigned pledge of value wei, see Wormholes.TokenPledgeWei
+func (b *TxBuilder) TokenPledgeWei(proxySign []byte, proxyAddress string, value *big.Int) (*types.Transaction, error) {
+	return b.build(tokenPledgeWeiCall(proxySign, proxyAddress, value))
+}
+
+// TokenRevokesPledgeWei builds an unsigned revocation of value wei, see Wormholes.TokenRevokesPledgeWei
+func (b *TxBuilder) TokenRevokesPledgeWei(value *big.Int) (*types.Transaction, error) {
+	return b.build(tokenRevokesPledgeWeiCall(value))
+}
+
+// AdditionalPledgeAmountWei builds an unsigned exchanger pledge increase, see Wormholes.AdditionalPledgeAmountWei
+func (b *TxBuilder) AdditionalPledgeAmountWei(value *big.Int) (*types.Transaction, error) {
+	return b.build(additionalPledgeAmountWeiCall(value))
+}
+
+// RevokesPledgeAmountWei builds an unsigned exchanger pledge decrease, see Wormholes.RevokesPledgeAmountWei
+func (b *TxBuilder) RevokesPledgeAmountWei(value *big.Int) (*types.Transaction, error) {
+	return b.build(revokesPledgeAmountWeiCall(value))
+}
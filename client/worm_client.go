@@ -4,8 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -15,57 +15,200 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/wormholes-org/wormholes-client/tools"
 	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
 )
 
+// Wallet signs transactions and orders with its Signer
 type Wallet struct {
-	priKey string
+	signer Signer
+	err    error
+}
+
+// newWallet creates a Wallet for a hex encoded private key.
+// An invalid key is reported when the wallet is used.
+func newWallet(priKey string) Wallet {
+	signer, err := NewKeySigner(priKey)
+	if err != nil {
+		return Wallet{err: err}
+	}
+	return Wallet{signer: signer}
+}
+
+// Signer returns the signer of the wallet
+func (w *Wallet) Signer() (Signer, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	if w.signer == nil {
+		return nil, xerrors.New("the wallet has no signer")
+	}
+	return w.signer, nil
 }
 
 type Wormholes struct {
 	Wallet
 	c *rpc.Client
+
+	nonces     *nonceManager
+	gasMargin  uint64
+	dynamicFee bool
+	logger     Logger
+	dialErr    error
+
+	verifyOrders bool
+	expiryCheck  bool
+	expiryMargin uint64
+	dryRun       bool
+
+	resubscribeBackoff time.Duration
 }
 
+// Logger receives the diagnostics of the client, see tools.Logger.
+// A *slog.Logger can be used as is, tools.NewStdLogger writes to a standard library logger.
+type Logger = tools.Logger
+
+// DefaultGasMargin is the percentage added to the estimated gas of a transaction
+const DefaultGasMargin = 20
+
 // NewClient creates a new wormclient for the given URL and priKey.
 // when the rawurl is  nil, Initialize the wallet, can sign buyer, seller, exchange information.
 // when the rawurl is not nil, Initialize the NFT, can carry out nft related transactions.
+// When the node cannot be dialed, the calls of the client fail with a transport error
+// and the failure is reported to the logger set with SetLogger, NewClientWithSigner
+// returns the error instead.
 func NewClient(priKey, rawurl string) *Wormholes {
-	if rawurl == "" {
-		return &Wormholes{
-			Wallet{priKey: priKey},
-			nil,
-		}
-	} else {
+	worm := &Wormholes{
+		Wallet:       newWallet(priKey),
+		nonces:       newNonceManager(),
+		gasMargin:    DefaultGasMargin,
+		expiryCheck:  true,
+		expiryMargin: DefaultExpiryMargin,
+		logger:       tools.NopLogger{},
+	}
+	if rawurl != "" {
 		client, err := rpc.Dial(rawurl)
 		if err != nil {
-			log.Fatalf("failed to connect to Ethereum node: %v", err)
-			return &Wormholes{}
+			worm.dialErr = xerrors.Errorf("%w: %v", errNoConnection, err)
+			return worm
 		}
-		return &Wormholes{
-			Wallet{
-				priKey: priKey,
-			},
-			client,
+		worm.c = client
+	}
+	return worm
+}
+
+// NewClientWithSigner creates a new wormclient signing with signer.
+// As for NewClient, an empty rawurl creates a client which can only sign.
+func NewClientWithSigner(signer Signer, rawurl string) (*Wormholes, error) {
+	worm := &Wormholes{
+		Wallet:       Wallet{signer: signer},
+		nonces:       newNonceManager(),
+		gasMargin:    DefaultGasMargin,
+		expiryCheck:  true,
+		expiryMargin: DefaultExpiryMargin,
+		logger:       tools.NopLogger{},
+	}
+	if rawurl != "" {
+		client, err := rpc.Dial(rawurl)
+		if err != nil {
+			return nil, err
 		}
+		worm.c = client
 	}
+	return worm, nil
 }
 
 func (worm *Wormholes) CloseConnect() {
-	worm.c.Close()
+	if worm.c != nil {
+		worm.c.Close()
+	}
+}
+
+// call sends the request method to the node for the operation op
+func (worm *Wormholes) call(ctx context.Context, op string, result interface{}, method string, args ...interface{}) error {
+	if worm.c == nil {
+		return rpcError(op, worm.connError())
+	}
+	return rpcError(op, worm.c.CallContext(ctx, result, method, args...))
+}
+
+// connError is the error of the calls of a client which is not connected to a node
+func (worm *Wormholes) connError() error {
+	if worm.dialErr != nil {
+		return worm.dialErr
+	}
+	return errNoConnection
 }
 
 func (worm *Wormholes) UpdatePri(pri string) {
-	worm.priKey = pri
+	worm.Wallet = newWallet(pri)
+}
+
+// UpdateSigner replaces the signer of the client
+func (worm *Wormholes) UpdateSigner(signer Signer) {
+	worm.Wallet = Wallet{signer: signer}
+}
+
+// ResetNonce drops the nonce counted locally for account, the nonce of its next
+// transaction is read from the pending state of the node again.
+// This is needed when transactions of the account are also sent by other clients.
+func (worm *Wormholes) ResetNonce(account common.Address) {
+	worm.nonces.reset(account)
+}
+
+// SetDynamicFee selects whether the wormholes transactions are sent as EIP-1559 dynamic fee
+// transactions. When the node does not support the London fork, legacy transactions are sent.
+func (worm *Wormholes) SetDynamicFee(enabled bool) {
+	worm.dynamicFee = enabled
+}
+
+// SetGasMargin sets the percentage added to the estimated gas of every wormholes transaction,
+// the default is DefaultGasMargin.
+func (worm *Wormholes) SetGasMargin(percent uint64) {
+	worm.gasMargin = percent
+}
+
+// SetLogger sets the logger of the diagnostics of the client, nil discards them.
+// By default nothing is logged. The failure to dial the node of NewClient is reported to it.
+func (worm *Wormholes) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = tools.NopLogger{}
+	}
+	worm.logger = logger
+	if worm.dialErr != nil {
+		logger.Error("failed to connect to the node", "err", worm.dialErr)
+	}
+}
+
+// SetVerifyOrders selects whether the signatures of the buyer, seller and exchanger
+// orders are checked before a trade is sent. The buyer order must be signed by the
+// recipient of the trade, the seller order by the seller named by the buyer and the
+// exchanger authorization by its exchanger owner and for the client account.
+func (worm *Wormholes) SetVerifyOrders(enabled bool) {
+	worm.verifyOrders = enabled
+}
+
+// SetExpiryCheck selects whether the block numbers of the orders of a trade are
+// checked against the head of the chain before it is signed, which costs one more
+// request to the node. It is enabled by default.
+func (worm *Wormholes) SetExpiryCheck(enabled bool) {
+	worm.expiryCheck = enabled
+}
+
+// SetExpiryMargin sets the number of blocks the orders of a trade must remain valid
+// after the block the trade is expected in, the default is DefaultExpiryMargin.
+// Trades with orders expiring earlier are rejected before they are signed.
+func (worm *Wormholes) SetExpiryMargin(blocks uint64) {
+	worm.expiryMargin = blocks
 }
 
 // ChainID retrieves the current chain ID for transaction replay protection.
 func (worm *Wormholes) ChainID(ctx context.Context) (*big.Int, error) {
 	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_chainId")
+	err := worm.call(ctx, "ChainID", &result, "eth_chainId")
 	if err != nil {
 		return nil, err
 	}
-	return (*big.Int)(&result), err
+	return (*big.Int)(&result), nil
 }
 
 // BlockByNumber returns a block from the current canonical chain. If number is nil, the
@@ -74,7 +217,11 @@ func (worm *Wormholes) ChainID(ctx context.Context) (*big.Int, error) {
 // Note that loading full blocks requires two requests. Use HeaderByNumber
 // if you don't need all transactions or uncle headers.
 func (worm *Wormholes) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
-	return worm.getBlock(ctx, "eth_getBlockByNumber", toBlockNumArg(number), true)
+	block, err := worm.getBlock(ctx, "eth_getBlockByNumber", toBlockNumArg(number), true)
+	if err != nil {
+		return nil, rpcError("BlockByNumber", err)
+	}
+	return block, nil
 }
 
 type rpcBlock struct {
@@ -84,6 +231,9 @@ type rpcBlock struct {
 }
 
 func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...interface{}) (*types.Block, error) {
+	if worm.c == nil {
+		return nil, worm.connError()
+	}
 	var raw json.RawMessage
 	err := worm.c.CallContext(ctx, &raw, method, args...)
 	if err != nil {
@@ -151,18 +301,24 @@ func (worm *Wormholes) getBlock(ctx context.Context, method string, args ...inte
 // BlockNumber returns the most recent block number
 func (worm *Wormholes) BlockNumber(ctx context.Context) (uint64, error) {
 	var result hexutil.Uint64
-	err := worm.c.CallContext(ctx, &result, "eth_blockNumber")
+	err := worm.call(ctx, "BlockNumber", &result, "eth_blockNumber")
 	return uint64(result), err
 }
 
 func (worm *Wormholes) GetBlockByNumber(ctx context.Context, number *big.Int) (map[string]interface{}, error) {
 	var raw json.RawMessage
 	block := make(map[string]interface{})
-	worm.c.CallContext(ctx, &raw, "eth_getBlockByNumber", toBlockNumArg(number), true)
-	err := json.Unmarshal(raw, &block)
+	err := worm.call(ctx, "GetBlockByNumber", &raw, "eth_getBlockByNumber", toBlockNumArg(number), true)
 	if err != nil {
 		return nil, err
 	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, opError("GetBlockByNumber", nil, ethereum.NotFound)
+	}
+	err = json.Unmarshal(raw, &block)
+	if err != nil {
+		return nil, opError("GetBlockByNumber", nil, err)
+	}
 	return block, nil
 }
 
@@ -187,26 +343,26 @@ func (tx *rpcTransaction) UnmarshalJSON(msg []byte) error {
 // TransactionInBlock returns a single transaction at index in the given block.
 func (worm *Wormholes) TransactionInBlock(ctx context.Context, blockHash common.Hash, index uint) (*types.Transaction, error) {
 	var json *rpcTransaction
-	err := worm.c.CallContext(ctx, &json, "eth_getTransactionByBlockHashAndIndex", blockHash, hexutil.Uint64(index))
+	err := worm.call(ctx, "TransactionInBlock", &json, "eth_getTransactionByBlockHashAndIndex", blockHash, hexutil.Uint64(index))
 	if err != nil {
 		return nil, err
 	}
 	if json == nil {
-		return nil, ethereum.NotFound
+		return nil, opError("TransactionInBlock", nil, ethereum.NotFound)
 	} else if _, r, _ := json.tx.RawSignatureValues(); r == nil {
-		return nil, fmt.Errorf("server returned transaction without signature")
+		return nil, opError("TransactionInBlock", nil, fmt.Errorf("server returned transaction without signature"))
 	}
 	if json.From != nil && json.BlockHash != nil {
 		setSenderFromServer(json.tx, *json.From, *json.BlockHash)
 	}
-	return json.tx, err
+	return json.tx, nil
 }
 
 // PendingNonceAt returns the account nonce of the given account in the pending state.
 // This is the nonce that should be used for the next transaction.
 func (worm *Wormholes) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
 	var result hexutil.Uint64
-	err := worm.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
+	err := worm.call(ctx, "PendingNonceAt", &result, "eth_getTransactionCount", account, "pending")
 	return uint64(result), err
 }
 
@@ -214,7 +370,7 @@ func (worm *Wormholes) PendingNonceAt(ctx context.Context, account common.Addres
 // execution of a transaction.
 func (worm *Wormholes) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	var hex hexutil.Big
-	if err := worm.c.CallContext(ctx, &hex, "eth_gasPrice"); err != nil {
+	if err := worm.call(ctx, "SuggestGasPrice", &hex, "eth_gasPrice"); err != nil {
 		return nil, err
 	}
 	return (*big.Int)(&hex), nil
@@ -227,41 +383,153 @@ func (worm *Wormholes) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 func (worm *Wormholes) SendTransaction(ctx context.Context, tx *types.Transaction) error {
 	data, err := tx.MarshalBinary()
 	if err != nil {
-		return err
+		return invalid("SendTransaction", err)
+	}
+	_, err = worm.sendRawTransaction(ctx, "SendTransaction", data)
+	return err
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction based on
+// the current pending state of the backend blockchain. There is no guarantee that this is
+// the true gas limit requirement as other transactions may be added or removed by miners,
+// but it should provide a basis for setting a reasonable default.
+func (worm *Wormholes) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	var hex hexutil.Uint64
+	err := worm.call(ctx, "EstimateGas", &hex, "eth_estimateGas", toCallArg(msg))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(hex), nil
+}
+
+// CallContract executes a message call transaction, which is directly executed in the VM
+// of the node, but never mined into the blockchain.
+//
+// blockNumber selects the block height at which the call runs. It can be nil, in which
+// case the code is taken from the latest known block, or -1 for the pending state.
+func (worm *Wormholes) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var hex hexutil.Bytes
+	err := worm.call(ctx, "CallContract", &hex, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return hex, nil
+}
+
+func toCallArg(msg ethereum.CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	if msg.GasFeeCap != nil {
+		arg["maxFeePerGas"] = (*hexutil.Big)(msg.GasFeeCap)
+	}
+	if msg.GasTipCap != nil {
+		arg["maxPriorityFeePerGas"] = (*hexutil.Big)(msg.GasTipCap)
+	}
+	return arg
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap after 1559 to
+// allow a timely execution of a transaction.
+func (worm *Wormholes) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	var hex hexutil.Big
+	if err := worm.call(ctx, "SuggestGasTipCap", &hex, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&hex), nil
+}
+
+type feeHistoryResultMarshaling struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory retrieves the fee market history of blockCount blocks ending at lastBlock,
+// if lastBlock is nil the history ends at the latest block. The returned base fees
+// include the base fee of the block following lastBlock.
+func (worm *Wormholes) FeeHistory(ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64) (*types2.FeeHistory, error) {
+	var res feeHistoryResultMarshaling
+	if err := worm.call(ctx, "FeeHistory", &res, "eth_feeHistory", hexutil.Uint(blockCount), toBlockNumArg(lastBlock), rewardPercentiles); err != nil {
+		return nil, err
+	}
+	reward := make([][]*big.Int, len(res.Reward))
+	for i, r := range res.Reward {
+		reward[i] = make([]*big.Int, len(r))
+		for j, r := range r {
+			reward[i][j] = (*big.Int)(r)
+		}
 	}
-	return worm.c.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Encode(data))
+	baseFee := make([]*big.Int, len(res.BaseFee))
+	for i, b := range res.BaseFee {
+		baseFee[i] = (*big.Int)(b)
+	}
+	return &types2.FeeHistory{
+		OldestBlock:  (*big.Int)(res.OldestBlock),
+		Reward:       reward,
+		BaseFee:      baseFee,
+		GasUsedRatio: res.GasUsedRatio,
+	}, nil
 }
 
 // NetworkID returns the network ID (also known as the chain ID) for this chain.
 func (worm *Wormholes) NetworkID(ctx context.Context) (*big.Int, error) {
 	version := new(big.Int)
 	var ver string
-	if err := worm.c.CallContext(ctx, &ver, "net_version"); err != nil {
+	if err := worm.call(ctx, "NetworkID", &ver, "net_version"); err != nil {
 		return nil, err
 	}
 	if _, ok := version.SetString(ver, 10); !ok {
-		return nil, fmt.Errorf("invalid net_version result %q", ver)
+		return nil, opError("NetworkID", nil, fmt.Errorf("invalid net_version result %q", ver))
 	}
 	return version, nil
 }
 
 // Balance returns the wei balance of the given account in the pending state.
 func (worm *Wormholes) Balance(ctx context.Context, account string) (*big.Int, error) {
+	err := tools.CheckAddress("account", account)
+	if err != nil {
+		return nil, invalid("Balance", err)
+	}
 	var accounts common.Address
 	accounts = common.HexToAddress(account)
 	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_getBalance", accounts, "pending")
-	return (*big.Int)(&result), err
+	err = worm.call(ctx, "Balance", &result, "eth_getBalance", accounts, "pending")
+	if err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
 }
 
 // BalanceAt returns the wei balance of the given account.
 // The block number can be nil, in which case the balance is taken from the latest known block.
 func (worm *Wormholes) BalanceAt(ctx context.Context, account string, blockNumber *big.Int) (*big.Int, error) {
+	err := tools.CheckAddress("account", account)
+	if err != nil {
+		return nil, invalid("BalanceAt", err)
+	}
 	var accounts common.Address
 	accounts = common.HexToAddress(account)
 	var result hexutil.Big
-	err := worm.c.CallContext(ctx, &result, "eth_getBalance", accounts, toBlockNumArg(blockNumber))
-	return (*big.Int)(&result), err
+	err = worm.call(ctx, "BalanceAt", &result, "eth_getBalance", accounts, toBlockNumArg(blockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&result), nil
 }
 
 func toBlockNumArg(number *big.Int) string {
@@ -278,12 +546,16 @@ func toBlockNumArg(number *big.Int) string {
 // TransactionReceipt returns the receipt of a transaction by transaction hash.
 // Note that the receipt is not available for pending transactions.
 func (worm *Wormholes) TransactionReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	err := tools.CheckHex("txHash", txHash)
+	if err != nil {
+		return nil, invalid("TransactionReceipt", err)
+	}
 	txHashs := common.HexToHash(txHash)
 	var r *types.Receipt
-	err := worm.c.CallContext(ctx, &r, "eth_getTransactionReceipt", txHashs)
+	err = worm.call(ctx, "TransactionReceipt", &r, "eth_getTransactionReceipt", txHashs)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, opError("TransactionReceipt", nil, ethereum.NotFound)
 		}
 	}
 	return r, err
@@ -292,10 +564,10 @@ func (worm *Wormholes) TransactionReceipt(ctx context.Context, txHash string) (*
 func (worm *Wormholes) GetValidators(ctx context.Context, blockNumber int64) (*types2.ValidatorList, error) {
 	blockNrOrHash := rpc.BlockNumber(blockNumber)
 	var r *types2.ValidatorList
-	err := worm.c.CallContext(ctx, &r, "eth_getValidator", blockNrOrHash)
+	err := worm.call(ctx, "GetValidators", &r, "eth_getValidator", blockNrOrHash)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, opError("GetValidators", nil, ethereum.NotFound)
 		}
 	}
 
@@ -303,14 +575,18 @@ func (worm *Wormholes) GetValidators(ctx context.Context, blockNumber int64) (*t
 }
 
 func (worm *Wormholes) GetAccountInfo(ctx context.Context, address string, block int64) (*types2.Account, error) {
+	err := tools.CheckAddress("address", address)
+	if err != nil {
+		return nil, invalid("GetAccountInfo", err)
+	}
 	var addresss common.Address
 	addresss = common.HexToAddress(address)
 	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block))
 	var r *types2.Account
-	err := worm.c.CallContext(ctx, &r, "eth_getAccountInfo", addresss, blockNrOrHash)
+	err = worm.call(ctx, "GetAccountInfo", &r, "eth_getAccountInfo", addresss, blockNrOrHash)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, opError("GetAccountInfo", nil, ethereum.NotFound)
 		}
 	}
 	return r, err
@@ -319,27 +595,31 @@ func (worm *Wormholes) GetAccountInfo(ctx context.Context, address string, block
 func (worm *Wormholes) GetBlockBeneficiaryAddressByNumber(ctx context.Context, block int64) (*types2.BeneficiaryAddressList, error) {
 	blockNumber := rpc.BlockNumber(block)
 	var r *types2.BeneficiaryAddressList
-	err := worm.c.CallContext(ctx, &r, "eth_getBlockBeneficiaryAddressByNumber", blockNumber, true)
+	err := worm.call(ctx, "GetBlockBeneficiaryAddressByNumber", &r, "eth_getBlockBeneficiaryAddressByNumber", blockNumber, true)
 	if err == nil {
 		if r == nil {
-			return nil, ethereum.NotFound
+			return nil, opError("GetBlockBeneficiaryAddressByNumber", nil, ethereum.NotFound)
 		}
 	}
 	return r, err
 }
 
 func (worm *Wormholes) QueryMinerProxy(ctx context.Context, number int64, account string) (types2.MinerProxyList, error) {
+	err := tools.CheckAddress("account", account)
+	if err != nil {
+		return nil, invalid("QueryMinerProxy", err)
+	}
 	var result types2.MinerProxyList
 	nu := fmt.Sprintf("0x%x", number)
 	var accounts common.Address
 
 	accounts = common.HexToAddress(account)
 
-	err := worm.c.CallContext(ctx, &result, "eth_queryMinerProxy", nu, accounts)
+	err = worm.call(ctx, "QueryMinerProxy", &result, "eth_queryMinerProxy", nu, accounts)
 	if err != nil {
 		return nil, err
 	}
-	return result, err
+	return result, nil
 }
 
 func (w *Wallet) Sign(data []byte, priKey string) ([]byte, error) {
@@ -358,34 +638,50 @@ func (w *Wallet) Sign(data []byte, priKey string) ([]byte, error) {
 	return signature, nil
 }
 
-// SignBuyer
-// amount: The amount the buyer purchased the NFT, formatted as a hexadecimal string
-// nftAddress: The NFT address of the transaction. The format is a hexadecimal string. When this field is filled in, it means that the transaction has minted nft. When not filled, it means lazy transaction, and the nft has not been minted
-// exchanger: The exchange on which the transaction took place, formatted as a decimal string
-// blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
-// seller: Seller's address, formatted as a hexadecimal string
-func (w *Wallet) SignBuyer(amount, nftAddress, exchanger, blockNumber, seller string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
+// signMessage signs msg as an ethereum signed message and returns the hex encoded signature
+func (w *Wallet) signMessage(msg string) (string, error) {
+	signer, err := w.Signer()
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	signature, err := signer.SignHash(tools.SignHash([]byte(msg)))
+	if err != nil {
+		return "", err
 	}
+	signature[64] += 27
+	return hexutil.Encode(signature), nil
+}
 
-	msg := amount + nftAddress + exchanger + blockNumber + seller
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
+// SignTx signs the transaction with the wallet key for the given chain ID.
+// The wallet does not need a node connection, the transaction can be built with a TxBuilder
+// and the signed result broadcast with Wormholes.SendRawTransaction.
+func (w *Wallet) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer, err := w.Signer()
 	if err != nil {
 		return nil, err
 	}
+	return signer.SignTx(tx, chainID)
+}
 
-	signature[64] += 27
-
+// SignBuyer
+// amount: The amount the buyer purchased the NFT, formatted as a hexadecimal string
+// nftAddress: The NFT address of the transaction. The format is a hexadecimal string. When this field is filled in, it means that the transaction has minted nft. When not filled, it means lazy transaction, and the nft has not been minted
+// exchanger: The exchange on which the transaction took place, formatted as a decimal string
+// blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
+// seller: Seller's address, formatted as a hexadecimal string
+func (w *Wallet) SignBuyer(amount, nftAddress, exchanger, blockNumber, seller string) ([]byte, error) {
 	buyer := types2.Buyer{
 		Amount:      amount,
 		NFTAddress:  nftAddress,
 		Exchanger:   exchanger,
 		BlockNumber: blockNumber,
 		Seller:      seller,
-		Sig:         hexutil.Encode(signature),
 	}
+	signature, err := w.signMessage(buyer.Message())
+	if err != nil {
+		return nil, err
+	}
+	buyer.Sig = signature
 
 	result, err := json.Marshal(buyer)
 	if err != nil {
@@ -402,26 +698,17 @@ func (w *Wallet) SignBuyer(amount, nftAddress, exchanger, blockNumber, seller st
 //	exchanger:	The exchange on which the transaction took place, formatted as a decimal string
 //	blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignSeller1(amount, nftAddress, exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
-		return nil, err
-	}
-
-	msg := amount + nftAddress + exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
 	seller1 := types2.Seller1{
 		Amount:      amount,
 		NFTAddress:  nftAddress,
 		Exchanger:   exchanger,
 		BlockNumber: blockNumber,
-		Sig:         hexutil.Encode(signature),
 	}
+	signature, err := w.signMessage(seller1.Message())
+	if err != nil {
+		return nil, err
+	}
+	seller1.Sig = signature
 
 	result, err := json.Marshal(seller1)
 	if err != nil {
@@ -440,19 +727,6 @@ func (w *Wallet) SignSeller1(amount, nftAddress, exchanger, blockNumber string)
 //	exchanger:	The exchange on which the transaction took place, formatted as a decimal string
 //	blockNumber: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignSeller2(amount, royalty, metaURL, exclusiveFlag, exchanger, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
-		return nil, err
-	}
-
-	msg := amount + royalty + metaURL + exclusiveFlag + exchanger + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
 	seller2 := types2.Seller2{
 		Amount:        amount,
 		Royalty:       royalty,
@@ -460,8 +734,12 @@ func (w *Wallet) SignSeller2(amount, royalty, metaURL, exclusiveFlag, exchanger,
 		ExclusiveFlag: exclusiveFlag,
 		Exchanger:     exchanger,
 		BlockNumber:   blockNumber,
-		Sig:           hexutil.Encode(signature),
 	}
+	signature, err := w.signMessage(seller2.Message())
+	if err != nil {
+		return nil, err
+	}
+	seller2.Sig = signature
 
 	result, err := json.Marshal(seller2)
 	if err != nil {
@@ -477,25 +755,16 @@ func (w *Wallet) SignSeller2(amount, royalty, metaURL, exclusiveFlag, exchanger,
 //	to: Authorized exchange, formatted as a hexadecimal string
 //	block_number: Block height, which means that this transaction is valid before this height, the format is a hexadecimal string
 func (w *Wallet) SignExchanger(exchangerOwner, to, blockNumber string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
-		return nil, err
-	}
-
-	msg := exchangerOwner + to + blockNumber
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
-	if err != nil {
-		return nil, err
-	}
-
-	signature[64] += 27
-
 	exchangeAuth := types2.ExchangerAuth{
 		ExchangerOwner: exchangerOwner,
 		To:             to,
 		BlockNumber:    blockNumber,
-		Sig:            hexutil.Encode(signature),
 	}
+	signature, err := w.signMessage(exchangeAuth.Message())
+	if err != nil {
+		return nil, err
+	}
+	exchangeAuth.Sig = signature
 
 	result, err := json.Marshal(exchangeAuth)
 	if err != nil {
@@ -505,24 +774,17 @@ func (w *Wallet) SignExchanger(exchangerOwner, to, blockNumber string) ([]byte,
 }
 
 func (w *Wallet) SignDelegate(address, pledgeAcoount string) ([]byte, error) {
-	key, err := crypto.HexToECDSA(w.priKey)
-	if err != nil {
-		return nil, err
-	}
-
 	msg := address + pledgeAcoount
-	signature, err := crypto.Sign(tools.SignHash([]byte(msg)), key)
+	signature, err := w.signMessage(msg)
 	if err != nil {
 		return nil, err
 	}
-
-	signature[64] += 27
-	return []byte(hexutil.Encode(signature)), nil
+	return []byte(signature), nil
 }
 
 func (worm *Wormholes) GetRandom11ValidatorsWithOutProxy(ctx context.Context, number uint64) ([]common.Address, error) {
 	var res []common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getValidators", rpc.BlockNumber(number))
+	err := worm.call(ctx, "GetRandom11ValidatorsWithOutProxy", &res, "erb_getValidators", rpc.BlockNumber(number))
 	if err != nil {
 		return nil, err
 	}
@@ -531,7 +793,7 @@ func (worm *Wormholes) GetRandom11ValidatorsWithOutProxy(ctx context.Context, nu
 
 func (worm *Wormholes) GetRandom11ValidatorsWithProxy(ctx context.Context, number uint64) ([]common.Address, error) {
 	var res []common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getElevenValidatorsWithProxy", rpc.BlockNumber(number))
+	err := worm.call(ctx, "GetRandom11ValidatorsWithProxy", &res, "erb_getElevenValidatorsWithProxy", rpc.BlockNumber(number))
 	if err != nil {
 		return nil, err
 	}
@@ -540,7 +802,7 @@ func (worm *Wormholes) GetRandom11ValidatorsWithProxy(ctx context.Context, numbe
 
 func (worm *Wormholes) GetRealAddr(ctx context.Context, addr common.Address) (common.Address, error) {
 	var res common.Address
-	err := worm.c.CallContext(ctx, &res, "erb_getRealAddr", addr)
+	err := worm.call(ctx, "GetRealAddr", &res, "erb_getRealAddr", addr)
 	if err != nil {
 		return res, err
 	}
@@ -550,7 +812,7 @@ func (worm *Wormholes) GetRealAddr(ctx context.Context, addr common.Address) (co
 func (worm *Wormholes) GetCoefficientByNumber(ctx context.Context, number uint64) ([]*types2.BlockParticipants, error) {
 	blockNo := rpc.BlockNumber(number)
 	var res []*types2.BlockParticipants
-	err := worm.c.CallContext(ctx, &res, "erb_getCoefficientByNumber", blockNo)
+	err := worm.call(ctx, "GetCoefficientByNumber", &res, "erb_getCoefficientByNumber", blockNo)
 	if err != nil {
 		return res, err
 	}
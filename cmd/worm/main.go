@@ -0,0 +1,13 @@
+// Command worm sends the wormholes transactions and runs the queries of the client
+// from the command line, run worm -h for its commands.
+package main
+
+import (
+	"os"
+
+	"github.com/wormholes-org/wormholes-client/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
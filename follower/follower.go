@@ -0,0 +1,322 @@
+// Package follower follows the canonical chain of a wormholes node and feeds its blocks,
+// in order, to consumers such as the NFT indexer.
+//
+// The follower remembers the hashes of the last blocks it followed. When the parent of
+// a new block is not the last followed block, or the last followed block is not in the
+// chain anymore, the chain was reorganized: the consumers are rolled back to the fork
+// point and the blocks of the new chain are applied instead.
+//
+// The checkpoint of the follower, the next block and the hashes of the last blocks, is
+// saved in a file after each block, so a restarted process resumes where it stopped.
+//
+//	ix, err := indexer.Open("nfts", worm)
+//	if err != nil {
+//		return err
+//	}
+//	defer ix.Close()
+//	f, err := follower.New(worm, "follower.json", ix)
+//	if err != nil {
+//		return err
+//	}
+//	return f.Run(ctx)
+package follower
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"golang.org/x/xerrors"
+)
+
+// DefaultDepth is the number of the last blocks remembered by a follower, the deepest
+// reorganization it can roll back
+const DefaultDepth = 64
+
+// ErrReorgTooDeep is returned when none of the blocks remembered by the follower is in
+// the chain anymore, the consumers can not be rolled back
+var ErrReorgTooDeep = xerrors.New("the chain was reorganized deeper than the followed blocks")
+
+// Consumer consumes the blocks of the chain, in order
+type Consumer interface {
+	// ApplyBlock consumes block, the next block of the chain
+	ApplyBlock(ctx context.Context, block *types.Block) error
+	// Rollback undoes the blocks consumed from number on, number is then the next block
+	// to apply. It must undo at least the depth of the follower, and nothing when number
+	// is not below the next block.
+	Rollback(ctx context.Context, number uint64) error
+}
+
+// checkpoint is the state of a follower saved in its file
+type checkpoint struct {
+	Next   uint64        `json:"next"`   // next block to apply
+	Hashes []common.Hash `json:"hashes"` // hashes of the last applied blocks, up to the block next-1
+}
+
+// Follower applies the blocks of the canonical chain to consumers
+type Follower struct {
+	worm      *client.Wormholes
+	path      string
+	consumers []Consumer
+
+	mu       sync.Mutex // guards the settings and the checkpoint
+	logger   client.Logger
+	depth    int
+	interval time.Duration
+	cp       checkpoint
+	inStep   bool // whether the consumers applied the blocks of the checkpoint and no other
+}
+
+// New returns a follower reading the blocks with worm and applying them to the
+// consumers. Its checkpoint is saved in the file path, a new follower starts at the
+// genesis block.
+func New(worm *client.Wormholes, path string, consumers ...Consumer) (*Follower, error) {
+	f := &Follower{
+		worm:      worm,
+		path:      path,
+		consumers: consumers,
+		logger:    tools.NopLogger{},
+		depth:     DefaultDepth,
+		interval:  client.DefaultPollInterval,
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("read the checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &f.cp); err != nil {
+		return nil, xerrors.Errorf("the checkpoint %s is corrupt: %w", path, err)
+	}
+	return f, nil
+}
+
+// SetDepth sets the number of the last blocks remembered, DefaultDepth if 0
+func (f *Follower) SetDepth(depth int) {
+	if depth <= 0 {
+		depth = DefaultDepth
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.depth = depth
+}
+
+// SetLogger sets the logger of the diagnostics of the follower, nil discards them
+func (f *Follower) SetLogger(logger client.Logger) {
+	if logger == nil {
+		logger = tools.NopLogger{}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logger = logger
+}
+
+// SetPollInterval sets the interval at which Run polls the head of the chain between
+// the new heads, client.DefaultPollInterval if 0. A running Run keeps
+// the interval it started with.
+func (f *Follower) SetPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = client.DefaultPollInterval
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interval = interval
+}
+
+// Next returns the number of the next block to apply
+func (f *Follower) Next() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cp.Next
+}
+
+// Sync applies the blocks from the next block to the latest one and returns the number
+// of the next block to apply.
+//
+// The first Sync rolls the consumers back to the checkpoint, undoing a block applied
+// by a process which stopped before saving it, so the consumers must have applied the
+// blocks of the checkpoint.
+func (f *Follower) Sync(ctx context.Context) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.sync(ctx); err != nil {
+		f.inStep = false
+		return f.cp.Next, err
+	}
+	return f.cp.Next, nil
+}
+
+func (f *Follower) sync(ctx context.Context) error {
+	if !f.inStep {
+		if err := f.rollbackConsumers(ctx, f.cp.Next); err != nil {
+			return err
+		}
+		f.inStep = true
+	}
+	if err := f.checkLast(ctx); err != nil {
+		return err
+	}
+	head, err := f.worm.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for f.cp.Next <= head {
+		block, err := f.worm.BlockByNumber(ctx, new(big.Int).SetUint64(f.cp.Next))
+		if xerrors.Is(err, ethereum.NotFound) {
+			// the chain is shorter than head, it was reorganized meanwhile
+			return f.checkLast(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		if n := len(f.cp.Hashes); n > 0 && block.ParentHash() != f.cp.Hashes[n-1] {
+			next := f.cp.Next
+			if err := f.reorg(ctx); err != nil {
+				return err
+			}
+			if f.cp.Next == next {
+				return xerrors.Errorf("the parent of block %d is %s, not the block %s", next, block.ParentHash().Hex(), f.cp.Hashes[n-1].Hex())
+			}
+			continue
+		}
+		if err := f.apply(ctx, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply applies block to the consumers and saves it in the checkpoint
+func (f *Follower) apply(ctx context.Context, block *types.Block) error {
+	for _, consumer := range f.consumers {
+		if err := consumer.ApplyBlock(ctx, block); err != nil {
+			return xerrors.Errorf("apply block %d: %w", block.NumberU64(), err)
+		}
+	}
+	f.cp.Next++
+	f.cp.Hashes = append(f.cp.Hashes, block.Hash())
+	if len(f.cp.Hashes) > f.depth {
+		f.cp.Hashes = append([]common.Hash(nil), f.cp.Hashes[len(f.cp.Hashes)-f.depth:]...)
+	}
+	f.logger.Debug("block followed", "number", block.NumberU64(), "hash", block.Hash())
+	return f.save()
+}
+
+// checkLast checks that the last followed block is still in the chain, and rolls the
+// consumers back to the fork point if it is not
+func (f *Follower) checkLast(ctx context.Context) error {
+	n := len(f.cp.Hashes)
+	if n == 0 {
+		return nil
+	}
+	block, err := f.worm.BlockByNumber(ctx, new(big.Int).SetUint64(f.cp.Next-1))
+	if err == nil && block.Hash() == f.cp.Hashes[n-1] {
+		return nil
+	}
+	if err != nil && !xerrors.Is(err, ethereum.NotFound) {
+		return err
+	}
+	return f.reorg(ctx)
+}
+
+// reorg finds the last followed block which is still in the chain and rolls the
+// consumers back to the block after it
+func (f *Follower) reorg(ctx context.Context) error {
+	first := f.cp.Next - uint64(len(f.cp.Hashes))
+	for i := len(f.cp.Hashes) - 1; i >= 0; i-- {
+		number := first + uint64(i)
+		block, err := f.worm.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if xerrors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if block.Hash() != f.cp.Hashes[i] {
+			continue
+		}
+		f.logger.Warn("chain reorganized", "fork", number, "dropped", f.cp.Next-number-1)
+		// the checkpoint is saved first: if the process stops before the consumers are
+		// rolled back, the next Sync rolls them back to it
+		f.cp.Next = number + 1
+		f.cp.Hashes = f.cp.Hashes[:i+1]
+		if err := f.save(); err != nil {
+			return err
+		}
+		return f.rollbackConsumers(ctx, f.cp.Next)
+	}
+	return xerrors.Errorf("%w: none of the blocks %d to %d is in the chain", ErrReorgTooDeep, first, f.cp.Next-1)
+}
+
+// rollbackConsumers rolls the consumers back to the block number, in the reverse order
+// of the blocks applications
+func (f *Follower) rollbackConsumers(ctx context.Context, number uint64) error {
+	for i := len(f.consumers) - 1; i >= 0; i-- {
+		if err := f.consumers[i].Rollback(ctx, number); err != nil {
+			return xerrors.Errorf("roll back to block %d: %w", number, err)
+		}
+	}
+	return nil
+}
+
+// save writes the checkpoint to a temporary file renamed to the checkpoint file, so a
+// crash leaves the previous checkpoint or the new one
+func (f *Follower) save() error {
+	data, err := json.Marshal(f.cp)
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return xerrors.Errorf("save the checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return xerrors.Errorf("save the checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Run follows the chain until ctx is done or Sync fails, and returns the error.
+// The head of the chain is polled, on a websocket or IPC connection also at each new
+// head, so the blocks mined while a lost subscription is resubscribed are followed.
+func (f *Follower) Run(ctx context.Context) error {
+	f.mu.Lock()
+	interval := f.interval
+	f.mu.Unlock()
+	// the new heads only wake the follower up early: the subscription is resubscribed in
+	// the background after a connection loss, the blocks mined meanwhile are found by polling
+	heads := make(chan *types.Header, 16)
+	var subErr <-chan error
+	sub, err := f.worm.SubscribeNewHead(ctx, heads)
+	if err == nil {
+		defer sub.Unsubscribe()
+		subErr = sub.Err()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := f.Sync(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-heads:
+		case <-ticker.C:
+		case <-subErr:
+			// the subscription is gone, continue by polling
+			subErr = nil
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package indexer
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
+)
+
+// update collects the changes of a block, they are written in one batch with the
+// journal undoing them
+type update struct {
+	ix        *Indexer
+	number    uint64
+	batch     *leveldb.Batch
+	nfts      map[common.Address]*change
+	approvals map[approval]*approvalChange
+	nextNFT   *big.Int
+	oldNFT    *big.Int // nextNFT before the block
+}
+
+// change is an NFT changed by the block, old is nil when the NFT was not indexed before
+type change struct {
+	old, nft *NFT
+}
+
+// approval is an account allowed by owner to transfer all its NFTs
+type approval struct {
+	owner, account common.Address
+}
+
+type approvalChange struct {
+	old, approved bool
+}
+
+// undo is the journal of a block: the state it changed, as it was before the block
+type undo struct {
+	NextNFT   *hexutil.Big   `json:"nextNft"`
+	NFTs      []undoNFT      `json:"nfts"`
+	Approvals []undoApproval `json:"approvals"`
+}
+
+type undoNFT struct {
+	Address common.Address `json:"address"`
+	Old     *NFT           `json:"old"` // nil when the block indexed the NFT
+}
+
+type undoApproval struct {
+	Owner    common.Address `json:"owner"`
+	Account  common.Address `json:"account"`
+	Approved bool           `json:"approved"`
+}
+
+func (ix *Indexer) newUpdate(number uint64) (*update, error) {
+	nextNFT := new(big.Int).SetBytes(FirstNFT.Bytes())
+	data, err := ix.db.Get(nextNFTKey, nil)
+	if err == nil {
+		nextNFT = new(big.Int).SetBytes(data)
+	} else if err != leveldb.ErrNotFound {
+		return nil, err
+	}
+	return &update{
+		ix:        ix,
+		number:    number,
+		batch:     new(leveldb.Batch),
+		nfts:      make(map[common.Address]*change),
+		approvals: make(map[approval]*approvalChange),
+		nextNFT:   nextNFT,
+		oldNFT:    nextNFT,
+	}, nil
+}
+
+// apply applies a successful wormholes transaction the way the chain does
+func (u *update) apply(tx *client.DecodedTransaction) error {
+	payload := tx.Payload
+	switch payload.Type {
+	case types2.Mint:
+		u.mint(tx.From, tx.From, payload.Royalty, payload.MetaURL, payload.Exchanger)
+	case types2.Transfer:
+		return u.setOwner(payload.NFTAddress, tx.To)
+	case types2.Author, types2.AuthorRevoke:
+		nft, err := u.nft(payload.NFTAddress)
+		if err != nil {
+			return err
+		}
+		nft.Approved = common.Address{}
+		if payload.Type == types2.Author {
+			nft.Approved = tx.To
+		}
+	case types2.AccountAuthor, types2.AccountAuthorRevoke:
+		return u.setApproval(approval{tx.From, tx.To}, payload.Type == types2.AccountAuthor)
+	case types2.SNFTToERB:
+		return u.setOwner(payload.NFTAddress, common.Address{})
+	case types2.SNFTPledge, types2.SNFTRevokesPledge:
+		// the SNFTs are injected by the chain, a pledge shows their owner
+		nft, err := u.nftOrNew(payload.NFTAddress)
+		if err != nil {
+			return err
+		}
+		nft.Owner = tx.From
+		nft.Pledged = payload.Type == types2.SNFTPledge
+	case types2.TransactionNFT, types2.BuyerInitiatingTransaction, types2.FoundryTradeBuyer,
+		types2.FoundryExchange, types2.NftExchangeMatch, types2.FoundryExchangeInitiated, types2.FtDoesNotAuthorizeExchanges:
+		return u.trade(tx)
+	}
+	return nil
+}
+
+// trade gives the NFT of the seller order to the buyer or, for the foundry trades,
+// mints it for the buyer
+func (u *update) trade(tx *client.DecodedTransaction) error {
+	payload := tx.Payload
+	// the trades initiated by the buyer are sent by it, the others to it
+	buyer := tx.To
+	if payload.Type == types2.BuyerInitiatingTransaction || payload.Type == types2.FoundryTradeBuyer {
+		buyer = tx.From
+	}
+	if seller2 := payload.Seller2; seller2 != nil {
+		seller, err := seller2.Verify(common.Address{})
+		if err != nil {
+			return err
+		}
+		royalty, err := types2.ParseHexBig(seller2.Royalty)
+		if err != nil {
+			return xerrors.Errorf("seller2 royalty: %w", err)
+		}
+		u.mint(buyer, seller, uint32(royalty.Uint64()), seller2.MetaURL, seller2.Exchanger)
+		return nil
+	}
+	nftAddress := ""
+	if payload.Seller1 != nil {
+		nftAddress = payload.Seller1.NFTAddress
+	} else if payload.Buyer != nil {
+		nftAddress = payload.Buyer.NFTAddress
+	}
+	return u.setOwner(nftAddress, buyer)
+}
+
+// mint indexes a new NFT at the next NFT address
+func (u *update) mint(owner, creator common.Address, royalty uint32, metaURL, exchanger string) {
+	nft := &NFT{
+		Address: common.BigToAddress(u.nextNFT),
+		Owner:   owner,
+		Creator: creator,
+		Royalty: royalty,
+		MetaURL: metaURL,
+	}
+	if exchanger != "" {
+		nft.Exchanger = common.HexToAddress(exchanger)
+	}
+	u.nfts[nft.Address] = &change{nft: nft}
+	u.nextNFT = new(big.Int).Add(u.nextNFT, common.Big1)
+}
+
+// setOwner gives the NFT at nftAddress to owner, its approval is cleared
+func (u *update) setOwner(nftAddress string, owner common.Address) error {
+	nft, err := u.nftOrNew(nftAddress)
+	if err != nil {
+		return err
+	}
+	nft.Owner, nft.Approved = owner, common.Address{}
+	return nil
+}
+
+// setApproval allows or forbids the account of a to transfer all the NFTs of its owner
+func (u *update) setApproval(a approval, approved bool) error {
+	c, ok := u.approvals[a]
+	if !ok {
+		old, err := u.ix.db.Has(key(approvalPrefix, a.owner, a.account), nil)
+		if err != nil {
+			return err
+		}
+		c = &approvalChange{old: old}
+		u.approvals[a] = c
+	}
+	c.approved = approved
+	return nil
+}
+
+// nft returns the NFT at nftAddress as changed by the block
+func (u *update) nft(nftAddress string) (*NFT, error) {
+	// the address of an SNFT may be shorter than an address
+	if err := tools.CheckHex("nft address", nftAddress); err != nil {
+		return nil, err
+	}
+	address := common.HexToAddress(nftAddress)
+	if c, ok := u.nfts[address]; ok {
+		return c.nft, nil
+	}
+	old, err := u.ix.NFT(address)
+	if err != nil {
+		return nil, err
+	}
+	nft := *old
+	u.nfts[address] = &change{old: old, nft: &nft}
+	return &nft, nil
+}
+
+// nftOrNew is like nft but indexes the NFTs which are not, such as the SNFTs
+func (u *update) nftOrNew(nftAddress string) (*NFT, error) {
+	nft, err := u.nft(nftAddress)
+	if xerrors.Is(err, ethereum.NotFound) {
+		nft = &NFT{Address: common.HexToAddress(nftAddress)}
+		u.nfts[nft.Address] = &change{nft: nft}
+		return nft, nil
+	}
+	return nft, err
+}
+
+// finish adds the changed NFTs, their secondary indexes, the approvals, the NFT counter
+// and the journal of the block to the batch
+func (u *update) finish() error {
+	journal := undo{NextNFT: (*hexutil.Big)(u.oldNFT)}
+	for address, c := range u.nfts {
+		if c.old != nil {
+			deleteIndexes(u.batch, c.old)
+		}
+		c.nft.Block = u.number
+		if err := putNFT(u.batch, c.nft); err != nil {
+			return err
+		}
+		journal.NFTs = append(journal.NFTs, undoNFT{Address: address, Old: c.old})
+	}
+	for a, c := range u.approvals {
+		putApproval(u.batch, a, c.approved)
+		journal.Approvals = append(journal.Approvals, undoApproval{Owner: a.owner, Account: a.account, Approved: c.old})
+	}
+	u.batch.Put(nextNFTKey, u.nextNFT.Bytes())
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return err
+	}
+	u.batch.Put(journalKey(u.number), data)
+	return nil
+}
+
+// putNFT adds nft and its secondary indexes to batch
+func putNFT(batch *leveldb.Batch, nft *NFT) error {
+	data, err := json.Marshal(nft)
+	if err != nil {
+		return err
+	}
+	batch.Put(key(nftPrefix, nft.Address), data)
+	for _, k := range indexKeys(nft) {
+		batch.Put(k, nil)
+	}
+	return nil
+}
+
+// deleteIndexes adds the deletion of the secondary indexes of nft to batch
+func deleteIndexes(batch *leveldb.Batch, nft *NFT) {
+	for _, k := range indexKeys(nft) {
+		batch.Delete(k)
+	}
+}
+
+// indexKeys returns the keys of the secondary indexes of nft, the zero accounts are not indexed
+func indexKeys(nft *NFT) [][]byte {
+	var keys [][]byte
+	for _, index := range []struct {
+		prefix  []byte
+		account common.Address
+	}{
+		{ownerPrefix, nft.Owner},
+		{creatorPrefix, nft.Creator},
+		{exchangerPrefix, nft.Exchanger},
+	} {
+		if index.account != (common.Address{}) {
+			keys = append(keys, key(index.prefix, index.account, nft.Address))
+		}
+	}
+	return keys
+}
+
+func putApproval(batch *leveldb.Batch, a approval, approved bool) {
+	if approved {
+		batch.Put(key(approvalPrefix, a.owner, a.account), nil)
+	} else {
+		batch.Delete(key(approvalPrefix, a.owner, a.account))
+	}
+}
+
+func putUint64(batch *leveldb.Batch, k []byte, value uint64) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, value)
+	batch.Put(k, data)
+}
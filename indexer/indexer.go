@@ -0,0 +1,352 @@
+// Package indexer indexes the ownership of the NFTs of a wormholes chain in an embedded
+// LevelDB store, so the NFTs can be looked up by owner, creator and exchanger, which
+// GetAccountInfo can not do.
+//
+// The indexer reads the blocks with BlockByNumber and decodes their wormholes
+// transactions with client.DecodeTransaction. Only the successful transactions are
+// applied, their receipts are read from the node.
+//
+//	ix, err := indexer.Open("nfts", worm)
+//	if err != nil {
+//		return err
+//	}
+//	defer ix.Close()
+//	if _, err := ix.Sync(ctx); err != nil {
+//		return err
+//	}
+//	nfts, err := ix.ByOwner(owner)
+//
+// Each indexed block is journaled, Rollback undoes the last blocks when the chain is
+// reorganized. The follower package calls it.
+package indexer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"golang.org/x/xerrors"
+)
+
+// FirstNFT is the address of the first NFT minted on a wormholes chain, the next
+// ones are minted at the consecutive addresses
+var FirstNFT = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+// DefaultJournalDepth is the number of the last indexed blocks which can be rolled back
+const DefaultJournalDepth = 128
+
+// NFT is the indexed state of an NFT
+type NFT struct {
+	Address   common.Address `json:"address"`
+	Owner     common.Address `json:"owner"` // zero once exchanged for ERB
+	Creator   common.Address `json:"creator"`
+	Exchanger common.Address `json:"exchanger"`
+	Approved  common.Address `json:"approved"` // account allowed to transfer the NFT, zero if none
+	Royalty   uint32         `json:"royalty"`
+	MetaURL   string         `json:"metaUrl"`
+	Pledged   bool           `json:"pledged"`
+	Block     uint64         `json:"block"` // block of the last change
+}
+
+// The keys of the store. The secondary indexes are the prefix followed by the
+// indexed account and the NFT address, with an empty value.
+var (
+	nftPrefix       = []byte("n") // n + nft -> NFT
+	ownerPrefix     = []byte("o") // o + owner + nft
+	creatorPrefix   = []byte("c") // c + creator + nft
+	exchangerPrefix = []byte("e") // e + exchanger + nft
+	approvalPrefix  = []byte("a") // a + owner + approved account
+	journalPrefix   = []byte("j") // j + block number -> undo
+	nextBlockKey    = []byte("m:nextBlock")
+	nextNFTKey      = []byte("m:nextNFT")
+)
+
+// Indexer indexes the NFTs of the blocks read by a client
+type Indexer struct {
+	worm         *client.Wormholes
+	db           *leveldb.DB
+	logger       client.Logger
+	journalDepth uint64
+
+	mu sync.Mutex // serializes the updates
+}
+
+// Open opens the index stored in the directory path, creating it if needed,
+// and reads the blocks with worm.
+// A new index starts at the genesis block: the addresses of the minted NFTs are
+// only known when all the mints of the chain are indexed.
+func Open(path string, worm *client.Wormholes) (*Indexer, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("open the index %s: %w", path, err)
+	}
+	return &Indexer{worm: worm, db: db, logger: tools.NopLogger{}, journalDepth: DefaultJournalDepth}, nil
+}
+
+// Close closes the store of the index
+func (ix *Indexer) Close() error {
+	return ix.db.Close()
+}
+
+// SetLogger sets the logger of the diagnostics of the indexer, nil discards them
+func (ix *Indexer) SetLogger(logger client.Logger) {
+	if logger == nil {
+		logger = tools.NopLogger{}
+	}
+	ix.logger = logger
+}
+
+// SetJournalDepth sets the number of the last indexed blocks which can be rolled back,
+// DefaultJournalDepth if 0. The journals of the older blocks are deleted when the next
+// block is indexed.
+func (ix *Indexer) SetJournalDepth(depth uint64) {
+	if depth == 0 {
+		depth = DefaultJournalDepth
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.journalDepth = depth
+}
+
+// NextBlock returns the number of the next block to index
+func (ix *Indexer) NextBlock() (uint64, error) {
+	return ix.uint64(nextBlockKey)
+}
+
+// Sync indexes the blocks from the next block to the latest one and returns the
+// number of the next block to index
+func (ix *Indexer) Sync(ctx context.Context) (uint64, error) {
+	head, err := ix.worm.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		next, err := ix.NextBlock()
+		if err != nil || next > head {
+			return next, err
+		}
+		block, err := ix.worm.BlockByNumber(ctx, new(big.Int).SetUint64(next))
+		if err != nil {
+			return next, err
+		}
+		if err := ix.ApplyBlock(ctx, block); err != nil {
+			return next, err
+		}
+	}
+}
+
+// ApplyBlock indexes the wormholes transactions of block, which must be the next block
+func (ix *Indexer) ApplyBlock(ctx context.Context, block *types.Block) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	next, err := ix.uint64(nextBlockKey)
+	if err != nil {
+		return err
+	}
+	if block.NumberU64() != next {
+		return xerrors.Errorf("block %d can not be indexed, the next block is %d", block.NumberU64(), next)
+	}
+	u, err := ix.newUpdate(block.NumberU64())
+	if err != nil {
+		return err
+	}
+	for _, tx := range block.Transactions() {
+		decoded, err := client.DecodeTransaction(tx)
+		if xerrors.Is(err, client.ErrNotWormholes) {
+			continue
+		}
+		if err != nil {
+			ix.logger.Warn("wormholes transaction skipped", "hash", tx.Hash(), "err", err)
+			continue
+		}
+		receipt, err := ix.worm.TransactionReceipt(ctx, tx.Hash().Hex())
+		if err != nil {
+			return err
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			continue
+		}
+		if err := u.apply(decoded); err != nil {
+			ix.logger.Warn("wormholes transaction skipped", "hash", tx.Hash(), "op", decoded.Operation, "err", err)
+		}
+	}
+	if err := u.finish(); err != nil {
+		return err
+	}
+	putUint64(u.batch, nextBlockKey, next+1)
+	if next >= ix.journalDepth {
+		if err := ix.dropJournals(u.batch, next-ix.journalDepth); err != nil {
+			return err
+		}
+	}
+	if err := ix.db.Write(u.batch, nil); err != nil {
+		return xerrors.Errorf("write the index of block %d: %w", next, err)
+	}
+	ix.logger.Debug("block indexed", "number", next, "changes", len(u.nfts))
+	return nil
+}
+
+// Rollback undoes the indexed blocks from number on, number is then the next block to
+// index. Only the blocks of the journal can be undone.
+func (ix *Indexer) Rollback(ctx context.Context, number uint64) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	next, err := ix.uint64(nextBlockKey)
+	if err != nil {
+		return err
+	}
+	for ; next > number; next-- {
+		if err := ix.undo(next - 1); err != nil {
+			return err
+		}
+		ix.logger.Info("block rolled back", "number", next-1)
+	}
+	return nil
+}
+
+// undo undoes the last indexed block number with its journal
+func (ix *Indexer) undo(number uint64) error {
+	data, err := ix.db.Get(journalKey(number), nil)
+	if err == leveldb.ErrNotFound {
+		return xerrors.Errorf("block %d can not be rolled back, it is older than the journal", number)
+	}
+	if err != nil {
+		return err
+	}
+	var journal undo
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return xerrors.Errorf("the journal of block %d is corrupt: %w", number, err)
+	}
+	batch := new(leveldb.Batch)
+	for _, n := range journal.NFTs {
+		nft, err := ix.NFT(n.Address)
+		if err != nil {
+			return err
+		}
+		deleteIndexes(batch, nft)
+		if n.Old == nil {
+			batch.Delete(key(nftPrefix, n.Address))
+		} else if err := putNFT(batch, n.Old); err != nil {
+			return err
+		}
+	}
+	for _, a := range journal.Approvals {
+		putApproval(batch, approval{a.Owner, a.Account}, a.Approved)
+	}
+	batch.Put(nextNFTKey, journal.NextNFT.ToInt().Bytes())
+	batch.Delete(journalKey(number))
+	putUint64(batch, nextBlockKey, number)
+	if err := ix.db.Write(batch, nil); err != nil {
+		return xerrors.Errorf("roll back block %d: %w", number, err)
+	}
+	return nil
+}
+
+// NFT returns the indexed NFT at address, the error matches ethereum.NotFound when
+// it is not indexed
+func (ix *Indexer) NFT(address common.Address) (*NFT, error) {
+	data, err := ix.db.Get(key(nftPrefix, address), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ethereum.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var nft NFT
+	if err := json.Unmarshal(data, &nft); err != nil {
+		return nil, xerrors.Errorf("the index of the nft %s is corrupt: %w", address.Hex(), err)
+	}
+	return &nft, nil
+}
+
+// ByOwner returns the NFTs owned by owner, in the order of their addresses
+func (ix *Indexer) ByOwner(owner common.Address) ([]*NFT, error) {
+	return ix.nfts(ownerPrefix, owner)
+}
+
+// ByCreator returns the NFTs created by creator, in the order of their addresses
+func (ix *Indexer) ByCreator(creator common.Address) ([]*NFT, error) {
+	return ix.nfts(creatorPrefix, creator)
+}
+
+// ByExchanger returns the NFTs bound to the exchanger, in the order of their addresses
+func (ix *Indexer) ByExchanger(exchanger common.Address) ([]*NFT, error) {
+	return ix.nfts(exchangerPrefix, exchanger)
+}
+
+// Approvals returns the accounts allowed by owner to transfer all its NFTs, with AccountAuthor
+func (ix *Indexer) Approvals(owner common.Address) ([]common.Address, error) {
+	var approved []common.Address
+	it := ix.db.NewIterator(util.BytesPrefix(key(approvalPrefix, owner)), nil)
+	defer it.Release()
+	for it.Next() {
+		approved = append(approved, common.BytesToAddress(it.Key()[1+common.AddressLength:]))
+	}
+	return approved, it.Error()
+}
+
+// nfts returns the NFTs of the secondary index prefix for account
+func (ix *Indexer) nfts(prefix []byte, account common.Address) ([]*NFT, error) {
+	var nfts []*NFT
+	it := ix.db.NewIterator(util.BytesPrefix(key(prefix, account)), nil)
+	defer it.Release()
+	for it.Next() {
+		nft, err := ix.NFT(common.BytesToAddress(it.Key()[1+common.AddressLength:]))
+		if err != nil {
+			return nil, err
+		}
+		nfts = append(nfts, nft)
+	}
+	return nfts, it.Error()
+}
+
+func (ix *Indexer) uint64(k []byte) (uint64, error) {
+	data, err := ix.db.Get(k, nil)
+	if err == leveldb.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// dropJournals deletes in batch the journals of the blocks up to number, with the ones
+// left by a larger journal depth
+func (ix *Indexer) dropJournals(batch *leveldb.Batch, number uint64) error {
+	it := ix.db.NewIterator(&util.Range{Start: journalKey(0), Limit: journalKey(number + 1)}, nil)
+	defer it.Release()
+	for it.Next() {
+		batch.Delete(append([]byte(nil), it.Key()...))
+	}
+	if err := it.Error(); err != nil {
+		return xerrors.Errorf("drop the journals up to block %d: %w", number, err)
+	}
+	return nil
+}
+
+// journalKey returns the key of the journal of block number
+func journalKey(number uint64) []byte {
+	k := make([]byte, len(journalPrefix)+8)
+	copy(k, journalPrefix)
+	binary.BigEndian.PutUint64(k[len(journalPrefix):], number)
+	return k
+}
+
+// key returns the key of prefix followed by the addresses
+func key(prefix []byte, addresses ...common.Address) []byte {
+	k := append([]byte(nil), prefix...)
+	for _, address := range addresses {
+		k = append(k, address.Bytes()...)
+	}
+	return k
+}
@@ -0,0 +1,481 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// DecodeParams decodes the params of a request into args, in order.
+// Missing or invalid params are returned as the JSON-RPC errors of invalid params.
+func DecodeParams(params []json.RawMessage, args ...interface{}) error {
+	if len(params) < len(args) {
+		return &Error{Code: -32602, Message: fmt.Sprintf("missing value for required argument %d", len(params))}
+	}
+	for i, arg := range args {
+		if err := json.Unmarshal(params[i], arg); err != nil {
+			return &Error{Code: -32602, Message: fmt.Sprintf("invalid argument %d: %v", i, err)}
+		}
+	}
+	return nil
+}
+
+// chain is the chain kept by a node: the blocks mined, the receipts of their
+// transactions and the nonces used by the accounts
+type chain struct {
+	mu       sync.Mutex
+	chainID  *big.Int
+	signer   types.Signer
+	autoMine bool
+	baseFee  *big.Int
+	state    *State
+	subs     *subscriptions
+	forks    uint64 // number of reorganizations, in the extra data of the blocks
+
+	blocks   []*types.Block
+	pending  []*types.Transaction
+	txs      []*types.Transaction
+	senders  map[common.Hash]common.Address
+	receipts map[common.Hash]*types.Receipt
+	nonces   map[common.Address]map[uint64]bool
+	balances map[common.Address]*big.Int
+}
+
+func newChain(chainID *big.Int) *chain {
+	genesis := types.NewBlock(&types.Header{
+		Number:     new(big.Int),
+		GasLimit:   30000000,
+		Difficulty: common.Big1,
+	}, nil, nil, nil, trie.NewStackTrie(nil))
+	return &chain{
+		chainID:  chainID,
+		signer:   types.LatestSignerForChainID(chainID),
+		autoMine: true,
+		blocks:   []*types.Block{genesis},
+		senders:  make(map[common.Hash]common.Address),
+		receipts: make(map[common.Hash]*types.Receipt),
+		nonces:   make(map[common.Address]map[uint64]bool),
+		balances: make(map[common.Address]*big.Int),
+	}
+}
+
+// register sets the methods answered by the chain as the default answers of n
+func (c *chain) register(n *Node) {
+	for method, h := range map[string]Handler{
+		"eth_chainId":                             c.chainIDMethod,
+		"net_version":                             c.netVersion,
+		"eth_blockNumber":                         c.blockNumber,
+		"eth_gasPrice":                            c.gasPrice,
+		"eth_maxPriorityFeePerGas":                c.gasPrice,
+		"eth_feeHistory":                          c.feeHistory,
+		"eth_estimateGas":                         c.estimateGas,
+		"eth_call":                                c.call,
+		"eth_getBalance":                          c.getBalance,
+		"eth_getTransactionCount":                 c.getTransactionCount,
+		"eth_sendRawTransaction":                  c.sendRawTransaction,
+		"eth_getTransactionReceipt":               c.getTransactionReceipt,
+		"eth_getBlockByNumber":                    c.getBlockByNumber,
+		"eth_getBlockByHash":                      c.getBlockByHash,
+		"eth_getTransactionByBlockHashAndIndex":   c.getTransactionByBlockHashAndIndex,
+		"eth_getUncleByBlockHashAndIndex":         c.getUncle,
+		"eth_getBlockTransactionCountByNumber":    c.getBlockTransactionCountByNumber,
+		"eth_getTransactionByBlockNumberAndIndex": c.getTransactionByBlockNumberAndIndex,
+	} {
+		n.handlers[method] = h
+	}
+}
+
+func (c *chain) head() *types.Block {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *chain) chainIDMethod([]json.RawMessage) (interface{}, error) {
+	return (*hexutil.Big)(c.chainID), nil
+}
+
+func (c *chain) netVersion([]json.RawMessage) (interface{}, error) {
+	return c.chainID.String(), nil
+}
+
+func (c *chain) blockNumber([]json.RawMessage) (interface{}, error) {
+	return hexutil.Uint64(c.head().NumberU64()), nil
+}
+
+func (c *chain) gasPrice([]json.RawMessage) (interface{}, error) {
+	return (*hexutil.Big)(big.NewInt(DefaultGasPrice)), nil
+}
+
+func (c *chain) feeHistory(ps []json.RawMessage) (interface{}, error) {
+	var count hexutil.Uint64
+	var last rpc.BlockNumber
+	if err := DecodeParams(ps, &count, &last); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block, err := c.blockByNumber(last)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, &Error{Code: -32000, Message: "block not found"}
+	}
+	newest := block.NumberU64()
+	if uint64(count) > newest+1 {
+		count = hexutil.Uint64(newest + 1)
+	}
+	oldest := newest + 1 - uint64(count)
+	result := map[string]interface{}{
+		"oldestBlock":  (*hexutil.Big)(new(big.Int).SetUint64(oldest)),
+		"gasUsedRatio": make([]float64, count),
+	}
+	if c.baseFee != nil {
+		baseFees := make([]*hexutil.Big, count+1)
+		for i := range baseFees {
+			baseFees[i] = (*hexutil.Big)(c.baseFee)
+		}
+		result["baseFeePerGas"] = baseFees
+	}
+	return result, nil
+}
+
+func (c *chain) estimateGas([]json.RawMessage) (interface{}, error) {
+	return hexutil.Uint64(DefaultGasEstimate), nil
+}
+
+func (c *chain) call([]json.RawMessage) (interface{}, error) {
+	return hexutil.Bytes{}, nil
+}
+
+func (c *chain) getBalance(ps []json.RawMessage) (interface{}, error) {
+	var account common.Address
+	if err := DecodeParams(ps, &account); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	balance := c.balances[account]
+	if balance == nil {
+		balance = new(big.Int)
+	}
+	return (*hexutil.Big)(balance), nil
+}
+
+func (c *chain) getTransactionCount(ps []json.RawMessage) (interface{}, error) {
+	var account common.Address
+	if err := DecodeParams(ps, &account); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return hexutil.Uint64(c.nextNonce(account)), nil
+}
+
+// nextNonce returns the first nonce of account which is not used
+func (c *chain) nextNonce(account common.Address) uint64 {
+	var nonce uint64
+	for c.nonces[account][nonce] {
+		nonce++
+	}
+	return nonce
+}
+
+func (c *chain) sendRawTransaction(ps []json.RawMessage) (interface{}, error) {
+	var raw hexutil.Bytes
+	if err := DecodeParams(ps, &raw); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return nil, err
+	}
+	from, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.nonces[from][tx.Nonce()] {
+		return nil, fmt.Errorf("nonce too low")
+	}
+	if c.state != nil {
+		cost := new(big.Int).Add(tx.Value(), fee(tx, tx.Gas()))
+		if balance := c.state.Balance(from); balance.Cmp(cost) < 0 {
+			return nil, fmt.Errorf("insufficient funds for gas * price + value: address %s have %s want %s", from.Hex(), balance, cost)
+		}
+	}
+	if c.nonces[from] == nil {
+		c.nonces[from] = make(map[uint64]bool)
+	}
+	c.nonces[from][tx.Nonce()] = true
+	c.senders[tx.Hash()] = from
+	c.txs = append(c.txs, tx)
+	c.pending = append(c.pending, tx)
+	c.subs.newPendingTransaction(tx.Hash())
+	if c.autoMine {
+		c.mine()
+	}
+	return tx.Hash(), nil
+}
+
+// mine mines the pending transactions in a new block. The transactions use their
+// intrinsic gas, they succeed unless they fail to execute against the state of the chain.
+func (c *chain) mine() *types.Block {
+	parent := c.blocks[len(c.blocks)-1]
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     new(big.Int).Add(parent.Number(), common.Big1),
+		GasLimit:   parent.GasLimit(),
+		Time:       parent.Time() + 1,
+		Difficulty: common.Big1,
+		BaseFee:    c.baseFee,
+		Extra:      new(big.Int).SetUint64(c.forks).Bytes(),
+	}
+	receipts := make([]*types.Receipt, len(c.pending))
+	for i, tx := range c.pending {
+		gas := intrinsicGas(tx)
+		header.GasUsed += gas
+		receipts[i] = &types.Receipt{
+			Type:              tx.Type(),
+			Status:            c.execute(tx, gas, header.Number.Uint64()),
+			CumulativeGasUsed: header.GasUsed,
+			Logs:              []*types.Log{},
+			TxHash:            tx.Hash(),
+			GasUsed:           gas,
+			TransactionIndex:  uint(i),
+		}
+	}
+	block := types.NewBlock(header, c.pending, nil, receipts, trie.NewStackTrie(nil))
+	for _, receipt := range receipts {
+		receipt.BlockHash = block.Hash()
+		receipt.BlockNumber = block.Number()
+		c.receipts[receipt.TxHash] = receipt
+	}
+	c.blocks = append(c.blocks, block)
+	c.pending = nil
+	c.subs.newHead(block.Header())
+	return block
+}
+
+// reorg removes the blocks after number, the blocks mined next fork the chain
+func (c *chain) reorg(number uint64) {
+	if number < uint64(len(c.blocks)) {
+		for _, block := range c.blocks[number+1:] {
+			for _, tx := range block.Transactions() {
+				delete(c.receipts, tx.Hash())
+				delete(c.nonces[c.senders[tx.Hash()]], tx.Nonce())
+			}
+		}
+		c.blocks = c.blocks[:number+1]
+	}
+	c.forks++
+}
+
+// execute executes tx against the state of the chain, when it has one, and returns the
+// status of its receipt. The sender pays the gas even if the transaction fails.
+func (c *chain) execute(tx *types.Transaction, gas, number uint64) uint64 {
+	if c.state == nil {
+		return types.ReceiptStatusSuccessful
+	}
+	msg := &message{from: c.senders[tx.Hash()], to: *tx.To(), value: tx.Value(), data: tx.Data()}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	balance := c.state.account(msg.from).Balance
+	cost := fee(tx, gas)
+	if balance.Cmp(cost) < 0 {
+		cost.Set(balance)
+	}
+	balance.Sub(balance, cost)
+	if err := c.state.execute(msg, number); err != nil {
+		return types.ReceiptStatusFailed
+	}
+	return types.ReceiptStatusSuccessful
+}
+
+// intrinsicGas returns the gas used by a transfer carrying the data of tx
+func intrinsicGas(tx *types.Transaction) uint64 {
+	gas := params.TxGas
+	for _, b := range tx.Data() {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGasEIP2028
+		}
+	}
+	if gas > tx.Gas() {
+		return tx.Gas()
+	}
+	return gas
+}
+
+func (c *chain) getTransactionReceipt(ps []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	if err := DecodeParams(ps, &hash); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	receipt, ok := c.receipts[hash]
+	if !ok {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// blockByNumber returns the block number, nil if it is not mined yet
+func (c *chain) blockByNumber(number rpc.BlockNumber) (*types.Block, error) {
+	switch {
+	case number == rpc.LatestBlockNumber || number == rpc.PendingBlockNumber:
+		return c.blocks[len(c.blocks)-1], nil
+	case number < 0:
+		return nil, &Error{Code: -32602, Message: fmt.Sprintf("unsupported block number %d", number)}
+	case int64(number) >= int64(len(c.blocks)):
+		return nil, nil
+	}
+	return c.blocks[number], nil
+}
+
+func (c *chain) blockByHash(hash common.Hash) *types.Block {
+	for _, block := range c.blocks {
+		if block.Hash() == hash {
+			return block
+		}
+	}
+	return nil
+}
+
+func (c *chain) getBlockByNumber(ps []json.RawMessage) (interface{}, error) {
+	var number rpc.BlockNumber
+	var full bool
+	if err := DecodeParams(ps, &number, &full); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block, err := c.blockByNumber(number)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return c.marshalBlock(block, full)
+}
+
+func (c *chain) getBlockByHash(ps []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	var full bool
+	if err := DecodeParams(ps, &hash, &full); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block := c.blockByHash(hash)
+	if block == nil {
+		return nil, nil
+	}
+	return c.marshalBlock(block, full)
+}
+
+func (c *chain) getTransactionByBlockHashAndIndex(ps []json.RawMessage) (interface{}, error) {
+	var hash common.Hash
+	var index hexutil.Uint64
+	if err := DecodeParams(ps, &hash, &index); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block := c.blockByHash(hash)
+	if block == nil || index >= hexutil.Uint64(len(block.Transactions())) {
+		return nil, nil
+	}
+	return c.marshalTransaction(block, int(index))
+}
+
+func (c *chain) getTransactionByBlockNumberAndIndex(ps []json.RawMessage) (interface{}, error) {
+	var number rpc.BlockNumber
+	var index hexutil.Uint64
+	if err := DecodeParams(ps, &number, &index); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block, err := c.blockByNumber(number)
+	if err != nil || block == nil || index >= hexutil.Uint64(len(block.Transactions())) {
+		return nil, err
+	}
+	return c.marshalTransaction(block, int(index))
+}
+
+func (c *chain) getBlockTransactionCountByNumber(ps []json.RawMessage) (interface{}, error) {
+	var number rpc.BlockNumber
+	if err := DecodeParams(ps, &number); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block, err := c.blockByNumber(number)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	return hexutil.Uint(len(block.Transactions())), nil
+}
+
+// getUncle answers the requests of uncles, the blocks of the node have none
+func (c *chain) getUncle([]json.RawMessage) (interface{}, error) {
+	return nil, nil
+}
+
+// marshalBlock returns the JSON fields of block, with the full transactions or their hashes
+func (c *chain) marshalBlock(block *types.Block, full bool) (map[string]interface{}, error) {
+	fields, err := toFields(block.Header())
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]interface{}, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		if !full {
+			txs[i] = tx.Hash()
+			continue
+		}
+		txs[i], err = c.marshalTransaction(block, i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	fields["transactions"] = txs
+	fields["uncles"] = []common.Hash{}
+	fields["size"] = hexutil.Uint64(block.Size())
+	return fields, nil
+}
+
+// marshalTransaction returns the JSON fields of the transaction at index in block
+func (c *chain) marshalTransaction(block *types.Block, index int) (map[string]interface{}, error) {
+	tx := block.Transactions()[index]
+	fields, err := toFields(tx)
+	if err != nil {
+		return nil, err
+	}
+	fields["blockHash"] = block.Hash()
+	fields["blockNumber"] = (*hexutil.Big)(block.Number())
+	fields["from"] = c.senders[tx.Hash()]
+	fields["transactionIndex"] = hexutil.Uint64(index)
+	return fields, nil
+}
+
+// toFields returns the fields of the JSON encoding of v
+func toFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	return fields, json.Unmarshal(data, &fields)
+}
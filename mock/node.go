@@ -0,0 +1,305 @@
+// Package mock provides an in-process wormholes node for the tests of the client.
+//
+// The node answers the JSON-RPC requests of the client over HTTP and websocket, where it
+// also notifies the subscriptions to new heads, pending transactions and logs. It keeps a
+// small chain of its own: the raw transactions it receives are checked for their nonce and
+// mined into blocks with successful receipts. Every method can be scripted with a fixed result, an
+// error or a handler, so the tests do not need a running wormholes node.
+//
+//	node := mock.NewNode()
+//	defer node.Close()
+//	node.SetResult("eth_getAccountInfo", &types.Account{Balance: big.NewInt(1)})
+//	worm := client.NewClient(priKey, node.URL())
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultChainID is the chain id of the node, it is also returned by net_version
+const DefaultChainID = 51888
+
+// DefaultGasPrice is the gas price suggested by the node
+const DefaultGasPrice = 1000000000
+
+// DefaultGasEstimate is the result of eth_estimateGas
+const DefaultGasEstimate = 50000
+
+// Handler answers a request with its params, as they were sent by the client.
+// The result is encoded as JSON, an error which is not an *Error is returned with the code -32000.
+type Handler func(params []json.RawMessage) (interface{}, error)
+
+// Error is a JSON-RPC error returned by the node
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the JSON-RPC error code of e
+func (e *Error) ErrorCode() int {
+	return e.Code
+}
+
+// Node is an in-process wormholes node serving JSON-RPC over HTTP
+type Node struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	calls    map[string]int
+	batches  int
+
+	chain *chain
+	subs  *subscriptions
+}
+
+// NewNode starts a node with a genesis block and the default answers of the methods
+// used by the client. It must be closed with Close.
+func NewNode() *Node {
+	n := &Node{
+		handlers: make(map[string]Handler),
+		calls:    make(map[string]int),
+		chain:    newChain(big.NewInt(DefaultChainID)),
+		subs:     newSubscriptions(),
+	}
+	n.chain.subs = n.subs
+	n.chain.register(n)
+	n.server = httptest.NewServer(n)
+	return n
+}
+
+// URL returns the HTTP endpoint of the node, to be given to client.NewClient
+func (n *Node) URL() string {
+	return n.server.URL
+}
+
+// Close stops the node, the requests sent later fail
+func (n *Node) Close() {
+	n.DropConnections()
+	n.server.Close()
+}
+
+// Handle answers method with h, replacing the current answer of the method
+func (n *Node) Handle(method string, h Handler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[method] = h
+}
+
+// Handler returns the current answer of method, nil if the method is not answered.
+// It allows a scripted handler to fall back to the default answer:
+//
+//	send := node.Handler("eth_sendRawTransaction")
+//	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+//		...
+//		return send(params)
+//	})
+func (n *Node) Handler(method string) Handler {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.handlers[method]
+}
+
+// SetResult answers method with result
+func (n *Node) SetResult(method string, result interface{}) {
+	n.Handle(method, func([]json.RawMessage) (interface{}, error) {
+		return result, nil
+	})
+}
+
+// SetError answers method with the JSON-RPC error code and message
+func (n *Node) SetError(method string, code int, message string) {
+	n.Handle(method, func([]json.RawMessage) (interface{}, error) {
+		return nil, &Error{Code: code, Message: message}
+	})
+}
+
+// Calls returns the number of requests of method received by the node
+func (n *Node) Calls(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+// Batches returns the number of batch requests received by the node
+func (n *Node) Batches() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.batches
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id,omitempty"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonError      `json:"error,omitempty"`
+}
+
+type jsonError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServeHTTP answers a single request or a batch of requests, and the requests of
+// the websocket connections
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		n.serveWebsocket(w, r)
+		return
+	}
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, err := n.serve(body, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// serve answers the single request or the batch of requests body received on conn,
+// nil for HTTP
+func (n *Node) serve(body []byte, conn *wsConn) (interface{}, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		return n.answer(&req, conn), nil
+	}
+	var reqs []*request
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		return nil, err
+	}
+	n.mu.Lock()
+	n.batches++
+	n.mu.Unlock()
+	resps := make([]*response, len(reqs))
+	for i, req := range reqs {
+		resps[i] = n.answer(req, conn)
+	}
+	return resps, nil
+}
+
+// answer runs the handler of the method of req
+func (n *Node) answer(req *request, conn *wsConn) *response {
+	n.mu.Lock()
+	h, ok := n.handlers[req.Method]
+	n.calls[req.Method]++
+	n.mu.Unlock()
+
+	switch req.Method {
+	case "eth_subscribe":
+		h, ok = func(params []json.RawMessage) (interface{}, error) {
+			return n.subs.subscribe(conn, params)
+		}, true
+	case "eth_unsubscribe":
+		h, ok = func(params []json.RawMessage) (interface{}, error) {
+			return n.subs.unsubscribe(conn, params)
+		}, true
+	}
+	resp := &response{Version: "2.0", ID: req.ID}
+	if !ok {
+		resp.Error = &jsonError{Code: -32601, Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method)}
+		return resp
+	}
+	result, err := h(req.Params)
+	if err != nil {
+		resp.Error = &jsonError{Code: -32000, Message: err.Error()}
+		if e, ok := err.(*Error); ok {
+			resp.Error.Code = e.Code
+		}
+		return resp
+	}
+	resp.Result, err = json.Marshal(result)
+	if err != nil {
+		resp.Error = &jsonError{Code: -32603, Message: err.Error()}
+	}
+	return resp
+}
+
+// ChainID returns the chain id of the node
+func (n *Node) ChainID() *big.Int {
+	return new(big.Int).Set(n.chain.chainID)
+}
+
+// BlockNumber returns the number of the latest block
+func (n *Node) BlockNumber() uint64 {
+	return n.chain.head().NumberU64()
+}
+
+// SetAutoMine selects whether every transaction received is mined at once in a block
+// of its own, which is the default. Otherwise the transactions wait for Mine.
+func (n *Node) SetAutoMine(enabled bool) {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	n.chain.autoMine = enabled
+}
+
+// Mine mines the pending transactions in a new block and returns it
+func (n *Node) Mine() *types.Block {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	return n.chain.mine()
+}
+
+// Reorg reorganizes the chain at the block number: the blocks after it are removed and
+// the next blocks are mined on top of it, with other hashes than the removed ones.
+// The transactions of the removed blocks are dropped with their receipts and their
+// nonces can be used again, the simulated state is not rolled back.
+func (n *Node) Reorg(number uint64) {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	n.chain.reorg(number)
+}
+
+// Transactions returns the transactions accepted by the node, in the order they were received
+func (n *Node) Transactions() []*types.Transaction {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	return append([]*types.Transaction(nil), n.chain.txs...)
+}
+
+// SetBalance sets the balance of account returned by eth_getBalance,
+// in the state of the node when it has one
+func (n *Node) SetBalance(account common.Address, balance *big.Int) {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	if n.chain.state != nil {
+		n.chain.state.SetBalance(account, balance)
+		return
+	}
+	n.chain.balances[account] = new(big.Int).Set(balance)
+}
+
+// SetBaseFee activates the London fork with the base fee of the next blocks,
+// nil deactivates it
+func (n *Node) SetBaseFee(baseFee *big.Int) {
+	n.chain.mu.Lock()
+	defer n.chain.mu.Unlock()
+	n.chain.baseFee = baseFee
+}
@@ -0,0 +1,497 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+)
+
+// wormholesPrefix prefixes the JSON payload in the data of the wormholes transactions
+const wormholesPrefix = "wormholes:"
+
+// State is a simulated wormholes state. When it is given to a node with SetState,
+// the node executes the transactions it mines against it and answers eth_getBalance,
+// eth_getAccountInfo, eth_call and the validator queries from it.
+//
+// The decoded payloads change the accounts the way the wormholes chain does:
+// Mint and the foundry trades create NFTs at consecutive addresses from
+// 0x0000000000000000000000000000000000000001, Transfer and the trades change their
+// owner, Author and AccountAuthor their approved addresses, Open and Close the
+// exchanger of the sender, and the pledges move ERB between the balance and the
+// pledged balances. The accounts with a pledged balance are the validators, in the
+// order of their addresses; TokenPledge and AccountDelegate set their proxy. Trades move the price from the buyer to the seller, exchanger
+// fees and royalties are not charged. SNFTs are not injected by VoteOfficialNFT,
+// they can be set with SetAccount.
+//
+// A transaction whose payload can not be applied is mined with a failed receipt,
+// it only pays its gas. The state is not versioned, queries at past blocks are
+// answered with the latest state.
+type State struct {
+	mu       sync.Mutex
+	accounts map[common.Address]*types2.Account
+	proxies  map[common.Address]common.Address // proxies of the validators
+	nextNFT  *big.Int
+}
+
+// NewState returns a state in which all accounts are empty
+func NewState() *State {
+	return &State{
+		accounts: make(map[common.Address]*types2.Account),
+		proxies:  make(map[common.Address]common.Address),
+		nextNFT:  big.NewInt(1),
+	}
+}
+
+// SetBalance sets the balance of account
+func (s *State) SetBalance(account common.Address, balance *big.Int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account(account).Balance = new(big.Int).Set(balance)
+}
+
+// Balance returns the balance of account
+func (s *State) Balance(account common.Address) *big.Int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return new(big.Int).Set(s.account(account).Balance)
+}
+
+// Account returns a copy of the account at address, as returned by eth_getAccountInfo
+func (s *State) Account(address common.Address) *types2.Account {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyAccount(s.account(address))
+}
+
+// SetAccount replaces the account at address, for instance to give an SNFT to an account
+func (s *State) SetAccount(address common.Address, account *types2.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[address] = copyAccount(account)
+	s.account(address)
+}
+
+// account returns the account at address, creating it when it does not exist
+func (s *State) account(address common.Address) *types2.Account {
+	a, ok := s.accounts[address]
+	if !ok {
+		a = new(types2.Account)
+		s.accounts[address] = a
+	}
+	for _, b := range []**big.Int{&a.Balance, &a.PledgedBalance, &a.ExchangerBalance, &a.VoteWeight, &a.BlockNumber} {
+		if *b == nil {
+			*b = new(big.Int)
+		}
+	}
+	return a
+}
+
+func copyAccount(a *types2.Account) *types2.Account {
+	c := *a
+	for _, b := range []**big.Int{&c.Balance, &c.PledgedBalance, &c.ExchangerBalance, &c.VoteWeight, &c.BlockNumber, &c.Price} {
+		if *b != nil {
+			*b = new(big.Int).Set(*b)
+		}
+	}
+	c.CodeHash = append([]byte(nil), a.CodeHash...)
+	c.ApproveAddressList = append([]common.Address(nil), a.ApproveAddressList...)
+	return &c
+}
+
+// copy returns a deep copy of s, the caller holds the lock of s
+func (s *State) copy() *State {
+	c := &State{
+		accounts: make(map[common.Address]*types2.Account, len(s.accounts)),
+		proxies:  make(map[common.Address]common.Address, len(s.proxies)),
+		nextNFT:  new(big.Int).Set(s.nextNFT),
+	}
+	for address, a := range s.accounts {
+		c.accounts[address] = copyAccount(a)
+	}
+	for validator, proxy := range s.proxies {
+		c.proxies[validator] = proxy
+	}
+	return c
+}
+
+// message is a transaction or a call executed against the state
+type message struct {
+	from  common.Address
+	to    common.Address
+	value *big.Int
+	data  []byte
+}
+
+// execute applies msg to a copy of s at the block number and replaces s with the
+// copy when it succeeds, the caller holds the lock of s
+func (s *State) execute(msg *message, number uint64) error {
+	next := s.copy()
+	if err := next.apply(msg, number); err != nil {
+		return err
+	}
+	s.accounts, s.proxies, s.nextNFT = next.accounts, next.proxies, next.nextNFT
+	return nil
+}
+
+// apply applies msg, the state is left partly modified when it fails
+func (s *State) apply(msg *message, number uint64) error {
+	if !bytes.HasPrefix(msg.data, []byte(wormholesPrefix)) {
+		return s.transfer(msg.from, msg.to, msg.value)
+	}
+	var tx types2.Transaction
+	if err := json.Unmarshal(msg.data[len(wormholesPrefix):], &tx); err != nil {
+		return fmt.Errorf("wormholes payload is wrong: %v", err)
+	}
+	if err := types2.MatchOrders(&tx, number); err != nil {
+		return err
+	}
+
+	from := s.account(msg.from)
+	switch tx.Type {
+	case types2.Mint:
+		s.mint(msg.from, msg.from, tx.Royalty, tx.MetaURL, tx.Exchanger)
+	case types2.Transfer:
+		return s.transferNFT(msg.from, tx.NFTAddress, msg.to)
+	case types2.Author, types2.AuthorRevoke:
+		nft, err := s.ownedNFT(msg.from, tx.NFTAddress)
+		if err != nil {
+			return err
+		}
+		nft.NFTApproveAddressList = common.Address{}
+		if tx.Type == types2.Author {
+			nft.NFTApproveAddressList = msg.to
+		}
+	case types2.AccountAuthor:
+		if !containsAddress(from.ApproveAddressList, msg.to) {
+			from.ApproveAddressList = append(from.ApproveAddressList, msg.to)
+		}
+	case types2.AccountAuthorRevoke:
+		list := from.ApproveAddressList[:0]
+		for _, address := range from.ApproveAddressList {
+			if address != msg.to {
+				list = append(list, address)
+			}
+		}
+		from.ApproveAddressList = list
+	case types2.SNFTToERB:
+		nft, err := s.ownedNFT(msg.from, tx.NFTAddress)
+		if err != nil {
+			return err
+		}
+		nft.Owner = common.Address{}
+		from.NFTBalance--
+	case types2.SNFTPledge, types2.SNFTRevokesPledge:
+		_, err := s.ownedNFT(msg.from, tx.NFTAddress)
+		return err
+	case types2.TokenPledge:
+		if tx.ProxyAddress != "" {
+			s.proxies[msg.from] = common.HexToAddress(tx.ProxyAddress)
+		}
+		return move(from.Balance, from.PledgedBalance, msg.value, "balance")
+	case types2.TokenRevokesPledge:
+		if err := move(from.PledgedBalance, from.Balance, msg.value, "pledged balance"); err != nil {
+			return err
+		}
+		if from.PledgedBalance.Sign() == 0 {
+			delete(s.proxies, msg.from)
+		}
+	case types2.AccountDelegate:
+		s.proxies[msg.from] = common.HexToAddress(tx.ProxyAddress)
+	case types2.Open:
+		if from.ExchangerFlag {
+			return fmt.Errorf("the account %s is already an exchanger", msg.from.Hex())
+		}
+		if err := move(from.Balance, from.ExchangerBalance, msg.value, "balance"); err != nil {
+			return err
+		}
+		from.ExchangerFlag = true
+		from.FeeRate, from.ExchangerName, from.ExchangerURL = tx.FeeRate, tx.Name, tx.Url
+		from.BlockNumber = new(big.Int).SetUint64(number)
+	case types2.Close:
+		if !from.ExchangerFlag {
+			return fmt.Errorf("the account %s is not an exchanger", msg.from.Hex())
+		}
+		from.Balance.Add(from.Balance, from.ExchangerBalance)
+		from.ExchangerBalance = new(big.Int)
+		from.ExchangerFlag = false
+	case types2.AdditionalPledgeAmount, types2.RevokesPledgeAmount:
+		if !from.ExchangerFlag {
+			return fmt.Errorf("the account %s is not an exchanger", msg.from.Hex())
+		}
+		if tx.Type == types2.AdditionalPledgeAmount {
+			return move(from.Balance, from.ExchangerBalance, msg.value, "balance")
+		}
+		return move(from.ExchangerBalance, from.Balance, msg.value, "exchanger balance")
+	case types2.TransactionNFT, types2.BuyerInitiatingTransaction, types2.FoundryTradeBuyer,
+		types2.FoundryExchange, types2.NftExchangeMatch, types2.FoundryExchangeInitiated, types2.FtDoesNotAuthorizeExchanges:
+		return s.trade(msg, &tx)
+	case types2.VoteOfficialNFT, types2.VoteOfficialNFTByApprovedExchanger, types2.UnforzenAccount:
+		// these transactions do not change the simulated accounts
+	default:
+		return fmt.Errorf("wormholes payload is wrong: unknown type %d", tx.Type)
+	}
+	return nil
+}
+
+// trade applies the trades of an NFT: the buyer pays the price to the seller and
+// gets the NFT of the seller order or, for the foundry trades, a new NFT
+func (s *State) trade(msg *message, tx *types2.Transaction) error {
+	var buyer, seller common.Address
+	var err error
+	switch tx.Type {
+	case types2.TransactionNFT:
+		seller = msg.from
+		buyer, err = tx.Buyer.Verify(msg.to)
+	case types2.BuyerInitiatingTransaction:
+		buyer = msg.from
+		seller, err = tx.Seller1.Verify(common.Address{})
+	case types2.FoundryTradeBuyer:
+		buyer = msg.from
+		seller, err = tx.Seller2.Verify(common.Address{})
+	default:
+		buyer, err = tx.Buyer.Verify(msg.to)
+		if err != nil {
+			return err
+		}
+		if tx.Seller1 != nil {
+			seller, err = tx.Seller1.Verify(common.Address{})
+		} else {
+			seller, err = tx.Seller2.Verify(common.Address{})
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.checkExchanger(msg.from, tx); err != nil {
+		return err
+	}
+
+	var price *big.Int
+	if tx.Buyer != nil {
+		price, err = tx.Buyer.Price()
+	} else if tx.Seller1 != nil {
+		price, err = tx.Seller1.Price()
+	} else {
+		price, err = tx.Seller2.Price()
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.transfer(buyer, seller, price); err != nil {
+		return err
+	}
+
+	if tx.Seller2 != nil {
+		royalty, err := types2.ParseHexBig(tx.Seller2.Royalty)
+		if err != nil {
+			return fmt.Errorf("seller2 royalty: %v", err)
+		}
+		s.mint(buyer, seller, uint32(royalty.Uint64()), tx.Seller2.MetaURL, tx.Seller2.Exchanger)
+		return nil
+	}
+	nftAddress := tx.Buyer.NFTAddress
+	if tx.Seller1 != nil {
+		nftAddress = tx.Seller1.NFTAddress
+	}
+	nft, err := s.ownedNFT(seller, nftAddress)
+	if err != nil {
+		return err
+	}
+	s.account(seller).NFTBalance--
+	s.account(buyer).NFTBalance++
+	nft.Owner, nft.NFTApproveAddressList = buyer, common.Address{}
+	return nil
+}
+
+// checkExchanger checks that the trades going through an exchanger are sent by an
+// open exchanger, or by the account it authorized
+func (s *State) checkExchanger(from common.Address, tx *types2.Transaction) error {
+	exchanger := from
+	switch tx.Type {
+	case types2.FoundryExchange, types2.FtDoesNotAuthorizeExchanges:
+	case types2.NftExchangeMatch, types2.FoundryExchangeInitiated:
+		owner, err := tx.ExchangerAuth.Verify()
+		if err != nil {
+			return err
+		}
+		if common.HexToAddress(tx.ExchangerAuth.To) != from {
+			return fmt.Errorf("the exchanger authorization is given to %s, not to the sender %s", tx.ExchangerAuth.To, from.Hex())
+		}
+		exchanger = owner
+	default:
+		return nil
+	}
+	if !s.account(exchanger).ExchangerFlag {
+		return fmt.Errorf("the account %s is not an exchanger", exchanger.Hex())
+	}
+	return nil
+}
+
+// mint creates an NFT at the next NFT address
+func (s *State) mint(owner, creator common.Address, royalty uint32, metaURL, exchanger string) {
+	address := common.BigToAddress(s.nextNFT)
+	s.nextNFT = new(big.Int).Add(s.nextNFT, common.Big1)
+	nft := s.account(address)
+	nft.Owner, nft.Creator = owner, creator
+	nft.Royalty, nft.MetaURL = royalty, metaURL
+	if exchanger != "" {
+		nft.Exchanger = common.HexToAddress(exchanger)
+	}
+	s.account(owner).NFTBalance++
+}
+
+// transferNFT gives the NFT at nftAddress to the account to, from must own the NFT
+// or be approved by its owner
+func (s *State) transferNFT(from common.Address, nftAddress string, to common.Address) error {
+	nft, ok := s.accounts[common.HexToAddress(nftAddress)]
+	if !ok || nft.Owner == (common.Address{}) {
+		return fmt.Errorf("the nft %s does not exist", nftAddress)
+	}
+	owner := s.account(nft.Owner)
+	if nft.Owner != from && nft.NFTApproveAddressList != from && !containsAddress(owner.ApproveAddressList, from) {
+		return fmt.Errorf("the account %s is neither the owner of the nft %s nor approved", from.Hex(), nftAddress)
+	}
+	owner.NFTBalance--
+	s.account(to).NFTBalance++
+	nft.Owner, nft.NFTApproveAddressList = to, common.Address{}
+	return nil
+}
+
+// ownedNFT returns the NFT at nftAddress, it must be owned by owner
+func (s *State) ownedNFT(owner common.Address, nftAddress string) (*types2.Account, error) {
+	nft, ok := s.accounts[common.HexToAddress(nftAddress)]
+	if !ok || nft.Owner == (common.Address{}) {
+		return nil, fmt.Errorf("the nft %s does not exist", nftAddress)
+	}
+	if nft.Owner != owner {
+		return nil, fmt.Errorf("the account %s is not the owner of the nft %s", owner.Hex(), nftAddress)
+	}
+	return nft, nil
+}
+
+// transfer moves value wei from the balance of from to the balance of to
+func (s *State) transfer(from, to common.Address, value *big.Int) error {
+	if value == nil || value.Sign() == 0 {
+		return nil
+	}
+	return move(s.account(from).Balance, s.account(to).Balance, value, "balance")
+}
+
+// move moves value from the balance src, named name, to the balance dst
+func move(src, dst, value *big.Int, name string) error {
+	if value == nil {
+		return nil
+	}
+	if src.Cmp(value) < 0 {
+		return fmt.Errorf("insufficient %s for %s wei", name, value)
+	}
+	src.Sub(src, value)
+	dst.Add(dst, value)
+	return nil
+}
+
+func containsAddress(list []common.Address, address common.Address) bool {
+	for _, a := range list {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// fee returns the fee paid for gas by tx
+func fee(tx *types.Transaction, gas uint64) *big.Int {
+	return new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(gas))
+}
+
+// SetState makes the node execute the transactions it mines against state, the
+// transactions whose sender can not pay their value and gas are rejected.
+// The balances, accounts and validators are then read from state, see State.
+func (n *Node) SetState(state *State) {
+	n.chain.mu.Lock()
+	n.chain.state = state
+	n.chain.mu.Unlock()
+
+	n.Handle("eth_getBalance", n.chain.stateBalance)
+	n.Handle("eth_getAccountInfo", n.chain.getAccountInfo)
+	n.Handle("eth_call", n.chain.stateCall)
+	n.chain.registerValidators(n)
+}
+
+func (c *chain) stateBalance(ps []json.RawMessage) (interface{}, error) {
+	var account common.Address
+	if err := DecodeParams(ps, &account); err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(c.state.Balance(account)), nil
+}
+
+func (c *chain) getAccountInfo(ps []json.RawMessage) (interface{}, error) {
+	var address common.Address
+	var block rpc.BlockNumberOrHash
+	if err := DecodeParams(ps, &address, &block); err != nil {
+		return nil, err
+	}
+	return c.state.Account(address), nil
+}
+
+// callArgs are the fields of the messages of eth_call
+type callArgs struct {
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasPrice     *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas *hexutil.Big    `json:"maxFeePerGas"`
+	Value        *hexutil.Big    `json:"value"`
+	Data         hexutil.Bytes   `json:"data"`
+}
+
+// cost returns the value of the message plus its gas at the highest price it pays
+func (args *callArgs) cost() *big.Int {
+	price := (*big.Int)(args.GasPrice)
+	if args.MaxFeePerGas != nil {
+		price = (*big.Int)(args.MaxFeePerGas)
+	}
+	cost := new(big.Int)
+	if price != nil {
+		cost.Mul(price, new(big.Int).SetUint64(uint64(args.Gas)))
+	}
+	if args.Value != nil {
+		cost.Add(cost, (*big.Int)(args.Value))
+	}
+	return cost
+}
+
+// stateCall executes the message of eth_call against a copy of the state at the
+// next block. As a node does, it rejects a sender who can not pay for the gas of
+// the message, but does not charge it.
+func (c *chain) stateCall(ps []json.RawMessage) (interface{}, error) {
+	var args callArgs
+	if err := DecodeParams(ps, &args); err != nil {
+		return nil, err
+	}
+	msg := &message{from: args.From, value: (*big.Int)(args.Value), data: args.Data}
+	if args.To != nil {
+		msg.to = *args.To
+	}
+	number := c.head().NumberU64() + 1
+
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	cost := args.cost()
+	if balance := c.state.account(args.From).Balance; balance.Cmp(cost) < 0 {
+		return nil, fmt.Errorf("insufficient funds for gas * price + value: address %s have %s want %s", args.From.Hex(), balance, cost)
+	}
+	if err := c.state.copy().apply(msg, number); err != nil {
+		return nil, fmt.Errorf("execution reverted: %v", err)
+	}
+	return hexutil.Bytes{}, nil
+}
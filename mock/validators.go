@@ -0,0 +1,170 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+)
+
+// elevenValidators is the number of validators of a block of the wormholes chain
+const elevenValidators = 11
+
+// registerValidators makes the node answer the validator queries from the state
+func (c *chain) registerValidators(n *Node) {
+	n.Handle("eth_getValidator", c.getValidator)
+	n.Handle("erb_getValidators", c.getElevenValidators)
+	n.Handle("erb_getElevenValidatorsWithProxy", c.getElevenValidatorsWithProxy)
+	n.Handle("erb_getRealAddr", c.getRealAddr)
+	n.Handle("erb_getCoefficientByNumber", c.getCoefficientByNumber)
+	n.Handle("eth_queryMinerProxy", c.queryMinerProxy)
+	n.Handle("eth_getBlockBeneficiaryAddressByNumber", c.getBlockBeneficiaryAddressByNumber)
+}
+
+// Validators returns the validators of the state, the accounts with a pledged
+// balance in the order of their addresses
+func (s *State) Validators() *types2.ValidatorList {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.validators()
+}
+
+// validators returns the validators of s, the caller holds the lock of s
+func (s *State) validators() *types2.ValidatorList {
+	list := &types2.ValidatorList{Validators: []*types2.Validator{}}
+	for address, a := range s.accounts {
+		if a.PledgedBalance == nil || a.PledgedBalance.Sign() == 0 {
+			continue
+		}
+		list.Validators = append(list.Validators, &types2.Validator{
+			Addr:    address,
+			Balance: new(big.Int).Set(a.PledgedBalance),
+			Proxy:   s.proxies[address],
+		})
+	}
+	sort.Slice(list.Validators, func(i, j int) bool {
+		return bytes.Compare(list.Validators[i].Addr.Bytes(), list.Validators[j].Addr.Bytes()) < 0
+	})
+	return list
+}
+
+// eleven returns the validators of the blocks, the first eleven validators of the state
+func (s *State) eleven() []*types2.Validator {
+	validators := s.validators().Validators
+	if len(validators) > elevenValidators {
+		validators = validators[:elevenValidators]
+	}
+	return validators
+}
+
+// The state is not versioned: the block numbers of the queries are decoded but
+// the latest state answers them.
+
+func (c *chain) getValidator(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumberOrHash
+	if err := DecodeParams(ps, &block); err != nil {
+		return nil, err
+	}
+	return c.state.Validators(), nil
+}
+
+func (c *chain) getElevenValidators(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumber
+	if err := DecodeParams(ps, &block); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	addresses := []common.Address{}
+	for _, v := range c.state.eleven() {
+		addresses = append(addresses, v.Addr)
+	}
+	return addresses, nil
+}
+
+func (c *chain) getElevenValidatorsWithProxy(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumber
+	if err := DecodeParams(ps, &block); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	addresses := []common.Address{}
+	for _, v := range c.state.eleven() {
+		if v.Proxy != (common.Address{}) {
+			addresses = append(addresses, v.Proxy)
+		} else {
+			addresses = append(addresses, v.Addr)
+		}
+	}
+	return addresses, nil
+}
+
+// getRealAddr returns the validator whose proxy is the address, or the address itself
+func (c *chain) getRealAddr(ps []json.RawMessage) (interface{}, error) {
+	var address common.Address
+	if err := DecodeParams(ps, &address); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	for validator, proxy := range c.state.proxies {
+		if proxy == address {
+			return validator, nil
+		}
+	}
+	return address, nil
+}
+
+func (c *chain) getCoefficientByNumber(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumber
+	if err := DecodeParams(ps, &block); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	participants := []*types2.BlockParticipants{}
+	for _, v := range c.state.eleven() {
+		participants = append(participants, &types2.BlockParticipants{
+			Address:     v.Addr,
+			Coefficient: c.state.accounts[v.Addr].Coefficient,
+		})
+	}
+	return participants, nil
+}
+
+// queryMinerProxy returns the proxy of the validator account, an empty list when it has none
+func (c *chain) queryMinerProxy(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumber
+	var account common.Address
+	if err := DecodeParams(ps, &block, &account); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	proxies := types2.MinerProxyList{}
+	if proxy, ok := c.state.proxies[account]; ok {
+		proxies = append(proxies, &types2.MinerProxy{Address: account, Proxy: proxy})
+	}
+	return proxies, nil
+}
+
+// getBlockBeneficiaryAddressByNumber returns the validators of the block as its beneficiaries
+func (c *chain) getBlockBeneficiaryAddressByNumber(ps []json.RawMessage) (interface{}, error) {
+	var block rpc.BlockNumber
+	var fullTx bool
+	if err := DecodeParams(ps, &block, &fullTx); err != nil {
+		return nil, err
+	}
+	c.state.mu.Lock()
+	defer c.state.mu.Unlock()
+	beneficiaries := types2.BeneficiaryAddressList{}
+	for _, v := range c.state.eleven() {
+		beneficiaries = append(beneficiaries, &types2.BeneficiaryAddress{Address: v.Addr})
+	}
+	return beneficiaries, nil
+}
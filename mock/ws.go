@@ -0,0 +1,237 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+)
+
+// wsConn is a websocket connection of a client, its writes are serialized
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *wsConn) write(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.WriteJSON(v)
+}
+
+// subscription is an eth_subscribe subscription of a websocket connection
+type subscription struct {
+	id     string
+	conn   *wsConn
+	kind   string // newHeads, newPendingTransactions or logs
+	filter *logFilter
+}
+
+type logFilter struct {
+	Address []common.Address `json:"address"`
+	Topics  [][]common.Hash  `json:"topics"`
+}
+
+// matches reports whether log matches the addresses and the topics of f
+func (f *logFilter) matches(log *types.Log) bool {
+	if len(f.Address) > 0 {
+		found := false
+		for _, address := range f.Address {
+			found = found || address == log.Address
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			found = found || topic == log.Topics[i]
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// subscriptions are the subscriptions of the websocket connections of a node
+type subscriptions struct {
+	mu    sync.Mutex
+	next  uint64
+	conns map[*wsConn]bool
+	subs  map[string]*subscription
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{
+		conns: make(map[*wsConn]bool),
+		subs:  make(map[string]*subscription),
+	}
+}
+
+// subscribe answers eth_subscribe on conn
+func (s *subscriptions) subscribe(conn *wsConn, params []json.RawMessage) (interface{}, error) {
+	if conn == nil {
+		return nil, &Error{Code: -32601, Message: "notifications not supported"}
+	}
+	var kind string
+	if err := DecodeParams(params, &kind); err != nil {
+		return nil, err
+	}
+	sub := &subscription{conn: conn, kind: kind}
+	switch kind {
+	case "newHeads", "newPendingTransactions":
+	case "logs":
+		sub.filter = new(logFilter)
+		if len(params) > 1 {
+			if err := json.Unmarshal(params[1], sub.filter); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("no %q subscription in eth namespace", kind)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.next++
+	sub.id = hexutil.EncodeUint64(s.next)
+	s.subs[sub.id] = sub
+	return sub.id, nil
+}
+
+// unsubscribe answers eth_unsubscribe on conn
+func (s *subscriptions) unsubscribe(conn *wsConn, params []json.RawMessage) (interface{}, error) {
+	var id string
+	if err := DecodeParams(params, &id); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subs[id]
+	if !ok || sub.conn != conn {
+		return nil, fmt.Errorf("subscription not found")
+	}
+	delete(s.subs, id)
+	return true, nil
+}
+
+func (s *subscriptions) add(conn *wsConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conns[conn] = true
+}
+
+// remove forgets conn and its subscriptions
+func (s *subscriptions) remove(conn *wsConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	for id, sub := range s.subs {
+		if sub.conn == conn {
+			delete(s.subs, id)
+		}
+	}
+}
+
+// notify sends result to the subscriptions of kind accepting it
+func (s *subscriptions) notify(kind string, result interface{}, accept func(*subscription) bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subs {
+		if sub.kind != kind || (accept != nil && !accept(sub)) {
+			continue
+		}
+		sub.conn.write(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "eth_subscription",
+			"params":  map[string]interface{}{"subscription": sub.id, "result": result},
+		})
+	}
+}
+
+func (s *subscriptions) newHead(header *types.Header) {
+	s.notify("newHeads", header, nil)
+}
+
+func (s *subscriptions) newPendingTransaction(hash common.Hash) {
+	s.notify("newPendingTransactions", hash, nil)
+}
+
+func (s *subscriptions) log(log *types.Log) {
+	s.notify("logs", log, func(sub *subscription) bool {
+		return sub.filter.matches(log)
+	})
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// serveWebsocket answers the requests of a websocket connection and sends it the
+// notifications of its subscriptions
+func (n *Node) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	c, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &wsConn{conn: c}
+	n.subs.add(conn)
+	defer func() {
+		n.subs.remove(conn)
+		c.Close()
+	}()
+	for {
+		_, body, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		resp, err := n.serve(body, conn)
+		if err != nil {
+			return
+		}
+		conn.write(resp)
+	}
+}
+
+// WebsocketURL returns the websocket endpoint of the node, to be given to client.NewClient
+// for the subscriptions
+func (n *Node) WebsocketURL() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+// Subscriptions returns the number of active subscriptions of the websocket connections
+func (n *Node) Subscriptions() int {
+	n.subs.mu.Lock()
+	defer n.subs.mu.Unlock()
+	return len(n.subs.subs)
+}
+
+// DropConnections closes the websocket connections, ending their subscriptions.
+// The clients reconnect with their next request.
+func (n *Node) DropConnections() {
+	n.subs.mu.Lock()
+	defer n.subs.mu.Unlock()
+	for conn := range n.subs.conns {
+		conn.conn.Close()
+	}
+	n.subs.conns = make(map[*wsConn]bool)
+	n.subs.subs = make(map[string]*subscription)
+}
+
+// EmitLog sends log to the log subscriptions it matches
+func (n *Node) EmitLog(log *types.Log) {
+	n.subs.log(log)
+}
@@ -0,0 +1,140 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/mock"
+	"github.com/wormholes-org/wormholes-client/tools"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+)
+
+func TestSendBatch(t *testing.T) {
+	node := newNode(t)
+	node.SetResult("eth_getTransactionCount", hexutil.Uint64(5))
+	send := node.Handler("eth_sendRawTransaction")
+	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		var raw hexutil.Bytes
+		if err := mock.DecodeParams(params, &raw); err != nil {
+			return nil, err
+		}
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(raw); err != nil {
+			return nil, err
+		}
+		if tx.Nonce() == 7 {
+			return nil, errors.New("insufficient funds for gas * price + value")
+		}
+		return send(params)
+	})
+	worm := client.NewClient(priKey, node.URL())
+
+	snft := func(address string) client.BatchOp {
+		return func(b *client.TxBuilder) (*types.Transaction, error) {
+			return b.SNFTToERB(address)
+		}
+	}
+	results, err := worm.SendBatch(context.Background(),
+		snft("0x8000000000000000000000000000000000000001"),
+		snft("invalid"),
+		snft("0x8000000000000000000000000000000000000002"),
+		snft("0x8000000000000000000000000000000000000003"),
+		snft("0x8000000000000000000000000000000000000004"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the gas is estimated in a first batch
+	if node.Batches() != 2 || node.Calls("eth_estimateGas") != 4 {
+		t.Fatalf("%d batches and %d estimates sent, want 2 and 4", node.Batches(), node.Calls("eth_estimateGas"))
+	}
+	// the invalid operation does not use a nonce, the node rejects nonce 7
+	var nonces []uint64
+	for _, tx := range node.Transactions() {
+		nonces = append(nonces, tx.Nonce())
+	}
+	if len(nonces) != 3 || nonces[0] != 5 || nonces[1] != 6 || nonces[2] != 8 {
+		t.Fatalf("nonces %v, want [5 6 8]", nonces)
+	}
+	if !errors.Is(results[1].Err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", results[1].Err, client.ErrValidation)
+	}
+	if !errors.Is(results[3].Err, client.ErrInsufficientFunds) || results[3].Hash != "" {
+		t.Fatalf("result %+v, want %v", results[3], client.ErrInsufficientFunds)
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Err != nil || len(results[i].Hash) != 66 {
+			t.Fatalf("result %d: %+v", i, results[i])
+		}
+	}
+	// the transaction after the rejected one waits for its nonce
+	if !errors.Is(results[4].Err, client.ErrNonceGap) || len(results[4].Hash) != 66 {
+		t.Fatalf("result %+v, want %v with its hash", results[4], client.ErrNonceGap)
+	}
+	for _, tx := range node.Transactions() {
+		if tx.Gas() != mock.DefaultGasEstimate*(100+client.DefaultGasMargin)/100 {
+			t.Fatalf("gas %d, want the estimate with its margin", tx.Gas())
+		}
+	}
+}
+
+func TestSendBatchOrders(t *testing.T) {
+	node := newNode(t)
+	node.SetResult("eth_blockNumber", hexutil.Uint64(0x600))
+	worm := client.NewClient(exchangerPriKey, node.URL())
+	seller := client.NewClient(sellerPriKey, "")
+	buyer := client.NewClient(buyerPriKey, "")
+
+	trade := func(deadline string) client.BatchOp {
+		seller1, _ := seller.SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline)
+		order, _ := buyer.SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline, "")
+		return func(b *client.TxBuilder) (*types.Transaction, error) {
+			return b.NFTDoesNotAuthorizeExchanges(order, seller1, buyerAddress)
+		}
+	}
+	results, err := worm.SendBatch(context.Background(), trade("0x60a"), trade("0x601"), trade("0x60b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the head is read once for all the orders
+	if node.Calls("eth_blockNumber") != 1 {
+		t.Fatalf("%d eth_blockNumber calls, want 1", node.Calls("eth_blockNumber"))
+	}
+	if !errors.Is(results[1].Err, types2.ErrOrderExpired) || results[1].Hash != "" {
+		t.Fatalf("result %+v, want %v", results[1], types2.ErrOrderExpired)
+	}
+	for _, i := range []int{0, 2} {
+		if results[i].Err != nil || len(results[i].Hash) != 66 {
+			t.Fatalf("result %d: %+v", i, results[i])
+		}
+	}
+}
+
+func TestSendBatchDryRunFees(t *testing.T) {
+	node, _ := newStateNode(t)
+	node.SetBaseFee(big.NewInt(7))
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetDynamicFee(true)
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	node.SetBalance(account, big.NewInt(1))
+
+	transfer := func(b *client.TxBuilder) (*types.Transaction, error) {
+		return b.NormalTransaction(sellerAddress, 0, "")
+	}
+	results, err := worm.DryRun().SendBatch(context.Background(), transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(results[0].Err, client.ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want %v", results[0].Err, client.ErrInsufficientFunds)
+	}
+	if node.Calls("eth_sendRawTransaction") != 0 {
+		t.Fatal("a dry run sent the transaction")
+	}
+}
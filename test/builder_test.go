@@ -0,0 +1,107 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+)
+
+func TestOfflineBuildAndSign(t *testing.T) {
+	account, _, err := tools.PriKeyToAddress(priKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builder := &client.TxBuilder{From: account, Nonce: 7, GasPrice: big.NewInt(1000000000)}
+
+	tx, err := builder.Transfer("0x0000000000000000000000000000000000000001", sellerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 7 || tx.Gas() != 50000 || *tx.To() != common.HexToAddress(sellerAddress) {
+		t.Fatalf("unexpected transaction nonce=%d gas=%d to=%s", tx.Nonce(), tx.Gas(), tx.To())
+	}
+	if !bytes.HasPrefix(tx.Data(), []byte("wormholes:")) {
+		t.Fatalf("missing wormholes prefix: %s", tx.Data())
+	}
+
+	worm := client.NewClient(priKey, "")
+	chainID := big.NewInt(51888)
+	signedTx, err := worm.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != account {
+		t.Fatalf("sender %s, want %s", sender, account)
+	}
+}
+
+func TestBuilderSendsToSelf(t *testing.T) {
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	builder := &client.TxBuilder{From: account, GasPrice: big.NewInt(1)}
+
+	tx, err := builder.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *tx.To() != account {
+		t.Fatalf("mint sent to %s, want %s", tx.To(), account)
+	}
+}
+
+func TestBuilderValidation(t *testing.T) {
+	builder := &client.TxBuilder{GasPrice: big.NewInt(1)}
+	_, err := builder.Transfer("0x01", "0x1234")
+	var opErr *client.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "TxBuilder" || !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want a TxBuilder validation error", err)
+	}
+	if _, err := builder.TransactionNFT([]byte("{"), buyerAddress); err == nil {
+		t.Fatal("expected malformed buyer to be rejected")
+	}
+}
+
+func TestOfflineDynamicFee(t *testing.T) {
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	chainID := big.NewInt(51888)
+	builder := &client.TxBuilder{
+		From:      account,
+		ChainID:   chainID,
+		GasTipCap: big.NewInt(1000000000),
+		GasFeeCap: big.NewInt(3000000000),
+	}
+
+	tx, err := builder.SNFTToERB("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Fatalf("transaction type %d, want %d", tx.Type(), types.DynamicFeeTxType)
+	}
+
+	signedTx, err := client.NewClient(priKey, "").SignTx(tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender != account {
+		t.Fatalf("sender %s, want %s", sender, account)
+	}
+
+	builder.ChainID = nil
+	if _, err := builder.Close(); err == nil {
+		t.Fatal("expected a dynamic fee transaction without chain id to be rejected")
+	}
+}
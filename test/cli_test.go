@@ -0,0 +1,200 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/cli"
+)
+
+// worm runs the worm command against node and returns its exit status, decoding
+// its JSON output into out
+func worm(t *testing.T, node string, out interface{}, args ...string) (int, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	status := cli.Run(append([]string{"-rpc", node}, args...), &stdout, &stderr)
+	if status == 0 && out != nil {
+		if err := json.Unmarshal(stdout.Bytes(), out); err != nil {
+			t.Fatalf("output %q: %v", stdout.String(), err)
+		}
+	}
+	return status, stderr.String()
+}
+
+func TestCLITransactions(t *testing.T) {
+	node, _ := newStateNode(t)
+	t.Setenv(cli.KeyEnv, sellerPriKey)
+
+	var sent map[string]string
+	status, stderr := worm(t, node.URL(), &sent, "mint", "-royalty", "10", "-meta-url", "/ipfs/ddfd90be9408b4", "-exchanger", exchangeAddress)
+	if status != 0 {
+		t.Fatalf("mint exit status %d: %s", status, stderr)
+	}
+	checkSent(t, node, sent["hash"], nil, "Mint")
+	// the royalty does not wrap around
+	status, stderr = worm(t, node.URL(), nil, "mint", "-royalty", "4294967306", "-meta-url", "/ipfs/ddfd90be9408b4")
+	if status == 0 || !strings.Contains(stderr, "-royalty is out of range") || node.Calls("eth_sendRawTransaction") != 1 {
+		t.Fatalf("mint with a royalty out of range: exit status %d: %s", status, stderr)
+	}
+
+	var account struct {
+		Owner   common.Address
+		MetaURL string
+	}
+	nftAddress := "0x0000000000000000000000000000000000000001"
+	if status, stderr := worm(t, node.URL(), &account, "account-info", "-address", nftAddress); status != 0 {
+		t.Fatalf("account-info exit status %d: %s", status, stderr)
+	}
+	if account.Owner != common.HexToAddress(sellerAddress) || account.MetaURL != "/ipfs/ddfd90be9408b4" {
+		t.Fatalf("minted nft %+v", account)
+	}
+
+	var result struct {
+		Hash   common.Hash
+		Status uint64
+	}
+	status, stderr = worm(t, node.URL(), &result, "-wait", "normal-transaction", "-to", buyerAddress, "-value", "1.5 ERB")
+	if status != 0 || result.Status != 1 {
+		t.Fatalf("normal-transaction exit status %d, result %+v: %s", status, result, stderr)
+	}
+	var balance map[string]string
+	if status, stderr := worm(t, node.URL(), &balance, "balance", "-address", buyerAddress); status != 0 {
+		t.Fatalf("balance exit status %d: %s", status, stderr)
+	}
+	if balance["erb"] != "1001.5" || balance["wei"] != "1001500000000000000000" {
+		t.Fatalf("balance %v", balance)
+	}
+
+	// the buyer does not own the NFT, the simulation fails and nothing is sent
+	sentTxs := len(node.Transactions())
+	t.Setenv(cli.KeyEnv, buyerPriKey)
+	status, stderr = worm(t, node.URL(), nil, "-dry-run", "transfer", "-nft", nftAddress, "-to", buyerAddress)
+	if status != 1 || !strings.Contains(stderr, `"error"`) {
+		t.Fatalf("dry run exit status %d: %s", status, stderr)
+	}
+	if len(node.Transactions()) != sentTxs {
+		t.Fatal("dry run sent a transaction")
+	}
+}
+
+func TestCLIErrors(t *testing.T) {
+	node := newNode(t)
+	t.Setenv(cli.KeyEnv, "")
+	if status, stderr := worm(t, node.URL(), nil, "close"); status != 1 || !strings.Contains(stderr, cli.KeyEnv) {
+		t.Fatalf("exit status %d without a key: %s", status, stderr)
+	}
+	if status, _ := worm(t, node.URL(), nil, "no-such-command"); status != 2 {
+		t.Fatalf("exit status %d for an unknown command", status)
+	}
+	t.Setenv(cli.KeyEnv, priKey)
+	if status, stderr := worm(t, node.URL(), nil, "normal-transaction", "-to", buyerAddress, "-value", "10"); status != 1 || !strings.Contains(stderr, "unit") {
+		t.Fatalf("exit status %d for an amount without unit: %s", status, stderr)
+	}
+	if status, stderr := worm(t, node.URL(), nil, "transaction-nft", "-to", buyerAddress); status != 1 || !strings.Contains(stderr, "-buyer") {
+		t.Fatalf("exit status %d without an order: %s", status, stderr)
+	}
+	if len(node.Transactions()) != 0 {
+		t.Fatal("invalid commands sent a transaction")
+	}
+}
+
+func TestCLIOrders(t *testing.T) {
+	node, _ := newStateNode(t)
+	dir := t.TempDir()
+	nftAddress := "0x0000000000000000000000000000000000000001"
+	send := func(key string, args ...string) {
+		t.Helper()
+		t.Setenv(cli.KeyEnv, key)
+		var result struct{ Status uint64 }
+		status, stderr := worm(t, node.URL(), &result, append([]string{"-wait"}, args...)...)
+		if status != 0 || result.Status != 1 {
+			t.Fatalf("%s exit status %d, result %+v: %s", args[0], status, result, stderr)
+		}
+	}
+	// sign writes the order signed with key to a file, and returns its name.
+	// No node listens on the port 0, the orders are signed offline.
+	sign := func(key, name string, args ...string) string {
+		t.Helper()
+		t.Setenv(cli.KeyEnv, key)
+		var order json.RawMessage
+		status, stderr := worm(t, "http://127.0.0.1:0", &order, append([]string{"sign"}, args...)...)
+		if status != 0 {
+			t.Fatalf("sign %s exit status %d: %s", args[0], status, stderr)
+		}
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, order, 0600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	send(sellerPriKey, "mint", "-royalty", "10", "-meta-url", "/ipfs/ddfd90be9408b4")
+	send(exchangerPriKey, "open", "-fee-rate", "10", "-name", "wormholes", "-url", "www.kang123456.com")
+	// the fee rate does not wrap around
+	if status, stderr := worm(t, node.URL(), nil, "open", "-fee-rate", "4294967306", "-name", "wormholes", "-url", "www.kang123456.com"); status == 0 || !strings.Contains(stderr, "-fee-rate is out of range") {
+		t.Fatalf("open with a fee rate out of range: exit status %d: %s", status, stderr)
+	}
+	seller1 := sign(sellerPriKey, "seller1.json", "seller1", "-price", "1 ERB", "-nft", nftAddress, "-exchanger", exchangeAddress, "-block-number", "1000")
+	buyer := sign(buyerPriKey, "buyer.json", "buyer", "-price", "0xde0b6b3a7640000", "-nft", nftAddress, "-exchanger", exchangeAddress, "-block-number", "0x3e8")
+
+	var signers []struct {
+		Order  string
+		Signer common.Address
+	}
+	auth := sign(exchangerPriKey, "auth.json", "exchanger", "-to", exchangeAddress1, "-block-number", "1000")
+	// each order is checked against its own signer
+	status, stderr := worm(t, node.URL(), &signers, "verify", "-buyer", "@"+buyer, "-seller1", "@"+seller1, "-exchanger-auth", "@"+auth,
+		"-buyer-signer", buyerAddress, "-seller-signer", sellerAddress, "-exchanger-signer", exchangeAddress)
+	if status != 0 {
+		t.Fatalf("verify exit status %d: %s", status, stderr)
+	}
+	if len(signers) != 3 || signers[0].Order != "buyer" || signers[0].Signer != common.HexToAddress(buyerAddress) ||
+		signers[1].Signer != common.HexToAddress(sellerAddress) || signers[2].Signer != common.HexToAddress(exchangeAddress) {
+		t.Fatalf("signers %+v", signers)
+	}
+	for _, flags := range [][]string{
+		{"-buyer", "@" + buyer, "-buyer-signer", sellerAddress},
+		{"-buyer", "@" + buyer, "-seller1", "@" + seller1, "-seller-signer", buyerAddress},
+		{"-exchanger-auth", "@" + auth, "-exchanger-signer", buyerAddress},
+	} {
+		status, stderr := worm(t, node.URL(), nil, append([]string{"verify"}, flags...)...)
+		if status != 1 || !strings.Contains(stderr, "signed by") {
+			t.Fatalf("verify %v exit status %d for the wrong signer: %s", flags, status, stderr)
+		}
+	}
+
+	send(exchangerPriKey, "nft-does-not-authorize-exchanges", "-buyer", "@"+buyer, "-seller1", "@"+seller1, "-to", buyerAddress)
+	var account struct{ Owner common.Address }
+	worm(t, node.URL(), &account, "account-info", "-address", nftAddress)
+	if account.Owner != common.HexToAddress(buyerAddress) {
+		t.Fatalf("nft owned by %s, want the buyer", account.Owner)
+	}
+}
+
+func TestCLISignSNFT(t *testing.T) {
+	t.Setenv(cli.KeyEnv, sellerPriKey)
+	// the address of an SNFT may be shorter than an address
+	snftAddress := "0x80000000000000000000000000000000000004"
+	for _, order := range []string{"buyer", "seller1"} {
+		var signed struct {
+			NFTAddress string `json:"nft_address"`
+		}
+		status, stderr := worm(t, "http://127.0.0.1:0", &signed, "sign", order, "-price", "1 ERB", "-nft", snftAddress, "-exchanger", exchangeAddress, "-block-number", "1000")
+		if status != 0 {
+			t.Fatalf("sign %s exit status %d: %s", order, status, stderr)
+		}
+		if signed.NFTAddress != snftAddress {
+			t.Fatalf("sign %s: nft address %q, want %q", order, signed.NFTAddress, snftAddress)
+		}
+
+		status, stderr = worm(t, "http://127.0.0.1:0", nil, "sign", order, "-price", "1 ERB", "-nft", "0x8000zz", "-exchanger", exchangeAddress, "-block-number", "1000")
+		if status == 0 || !strings.Contains(stderr, "-nft is not an address") {
+			t.Fatalf("sign %s with an invalid nft: exit status %d: %s", order, status, stderr)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wormholes-org/wormholes-client/client"
+)
+
+func TestContextDeadline(t *testing.T) {
+	// a node which does not answer until the test is over
+	release := make(chan struct{})
+	node := newNode(t)
+	node.Handle("net_version", func([]json.RawMessage) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	defer close(release)
+
+	worm := client.NewClient(priKey, node.URL())
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := worm.MintContext(ctx, 10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	types2 "github.com/wormholes-org/wormholes-client/types"
+	"golang.org/x/xerrors"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	chainID := big.NewInt(51888)
+	builder := &client.TxBuilder{From: account, ChainID: chainID, GasPrice: big.NewInt(1)}
+	worm := client.NewClient(priKey, "")
+
+	tx, err := builder.SNFTPledge("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedTx, err := worm.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := client.DecodeTransaction(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Operation != "SNFTPledge" || decoded.Payload.Type != types2.SNFTPledge {
+		t.Fatalf("operation %s type %d, want SNFTPledge", decoded.Operation, decoded.Payload.Type)
+	}
+	if decoded.From != account || decoded.To != account {
+		t.Fatalf("from %s to %s, want %s", decoded.From, decoded.To, account)
+	}
+	if decoded.Value.Cmp(signedTx.Value()) != 0 || decoded.Hash != signedTx.Hash() {
+		t.Fatal("value or hash of the decoded transaction differ")
+	}
+	if decoded.Payload.NFTAddress != "0x8000000000000000000000000000000000000004" {
+		t.Fatalf("nft address %s", decoded.Payload.NFTAddress)
+	}
+}
+
+func TestDecodeData(t *testing.T) {
+	tx, err := (&client.TxBuilder{GasPrice: big.NewInt(1)}).NormalTransaction(sellerAddress, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.DecodeTransaction(tx); !xerrors.Is(err, client.ErrNotWormholes) {
+		t.Fatalf("got %v, want ErrNotWormholes", err)
+	}
+
+	invalid := []string{
+		`wormholes:{`,
+		`wormholes:{"type":99,"version":"v0.0.1"}`,
+		`wormholes:{"type":1,"nft_address":"8000","version":"v0.0.1"}`,
+		`wormholes:{"type":14,"version":"v0.0.1"}`,
+	}
+	for _, data := range invalid {
+		if _, err := client.DecodeData([]byte(data)); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+
+	payload, err := client.DecodeData([]byte(`wormholes:{"type":14,"buyer":{"price":"0x1","block_number":"0x10"},"version":"v0.0.1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Buyer.Amount != "0x1" {
+		t.Fatalf("buyer price %s", payload.Buyer.Amount)
+	}
+}
+
+func TestDecodeUnsigned(t *testing.T) {
+	tx := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(0), []byte(`wormholes:{"type":12,"version":"v0.0.1"}`))
+	if _, err := client.DecodeTransaction(tx); err == nil {
+		t.Fatal("expected an unsigned transaction to be rejected")
+	}
+}
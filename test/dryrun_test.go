@@ -0,0 +1,75 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"github.com/wormholes-org/wormholes-client/unit"
+)
+
+func TestDryRun(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+
+	hash, err := worm.DryRun().SNFTPledge("0x8000000000000000000000000000000000000004")
+	if err != nil || hash != "" {
+		t.Fatalf("hash %q, err %v", hash, err)
+	}
+	if node.Calls("eth_call") != 1 || node.Calls("eth_sendRawTransaction") != 0 {
+		t.Fatal("the dry run was not simulated or sent the transaction")
+	}
+
+	// the client itself still sends
+	if _, err := worm.SNFTPledge("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	if node.Calls("eth_sendRawTransaction") != 1 {
+		t.Fatal("the transaction was not sent")
+	}
+}
+
+func TestDryRunRejected(t *testing.T) {
+	node := newNode(t)
+	node.SetError("eth_call", -32000, "not the owner of the nft")
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetDryRun(true)
+
+	_, err := worm.Transfer("0x0000000000000000000000000000000000000001", sellerAddress)
+	if !errors.Is(err, client.ErrPayloadRejected) {
+		t.Fatalf("err = %v, want %v", err, client.ErrPayloadRejected)
+	}
+	var opErr *client.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "Transfer" {
+		t.Fatalf("err = %v, want an error of Transfer", err)
+	}
+	if node.Calls("eth_sendRawTransaction") != 0 {
+		t.Fatal("a dry run sent the transaction")
+	}
+}
+
+func TestDryRunDynamicFee(t *testing.T) {
+	node, _ := newStateNode(t)
+	node.SetBaseFee(big.NewInt(7))
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetDynamicFee(true)
+	dry := worm.DryRun()
+
+	// the account can pay the value but not the gas at the fee cap
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	node.SetBalance(account, big.NewInt(1))
+	_, err := dry.NormalTransaction(sellerAddress, 0, "")
+	if !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want %v", err, client.ErrInsufficientFunds)
+	}
+
+	node.SetBalance(account, unit.ToWei(1, unit.ERB))
+	if _, err := dry.NormalTransaction(sellerAddress, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if node.Calls("eth_sendRawTransaction") != 0 {
+		t.Fatal("a dry run sent the transaction")
+	}
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/mock"
+)
+
+// rejectingNode starts a mock node which rejects eth_sendRawTransaction with message
+func rejectingNode(t *testing.T, message string) *mock.Node {
+	node := newNode(t)
+	node.SetError("eth_sendRawTransaction", -32000, message)
+	return node
+}
+
+func TestSendErrors(t *testing.T) {
+	tests := []struct {
+		message string
+		kind    error
+	}{
+		{"insufficient funds for gas * price + value", client.ErrInsufficientFunds},
+		{"replacement transaction underpriced", client.ErrUnderpriced},
+		{"nonce too low", client.ErrNonceConflict},
+		{"wormholes payload is wrong", client.ErrPayloadRejected},
+	}
+	for _, test := range tests {
+		node := rejectingNode(t, test.message)
+		worm := client.NewClient(priKey, node.URL())
+		_, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+
+		if !errors.Is(err, test.kind) {
+			t.Fatalf("%q: err = %v, want %v", test.message, err, test.kind)
+		}
+		var opErr *client.OpError
+		if !errors.As(err, &opErr) || opErr.Op != "Mint" {
+			t.Fatalf("%q: err = %v, want an error of Mint", test.message, err)
+		}
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	worm := client.NewClient(priKey, "")
+	_, err := worm.Transfer("0x0000000000000000000000000000000000000001", "0x1234")
+	if !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, client.ErrValidation)
+	}
+	if _, err := worm.Balance(context.Background(), "account"); !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, client.ErrValidation)
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	node := mock.NewNode()
+	worm := client.NewClient(priKey, node.URL())
+	node.Close()
+
+	_, err := worm.GetAccountInfo(context.Background(), exchangeAddress, 0)
+	if !errors.Is(err, client.ErrTransport) {
+		t.Fatalf("err = %v, want %v", err, client.ErrTransport)
+	}
+	var opErr *client.OpError
+	if !errors.As(err, &opErr) || opErr.Op != "GetAccountInfo" {
+		t.Fatalf("err = %v, want an error of GetAccountInfo", err)
+	}
+
+	if _, err := client.NewClient(priKey, "").BlockNumber(context.Background()); !errors.Is(err, client.ErrTransport) {
+		t.Fatalf("err = %v, want %v", err, client.ErrTransport)
+	}
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/types"
+)
+
+func TestOrderExpiry(t *testing.T) {
+	node := rejectingNode(t, "wormholes payload is wrong")
+	node.SetResult("eth_blockNumber", hexutil.Uint64(0x600))
+	worm := client.NewClient(exchangerPriKey, node.URL())
+	ctx := context.Background()
+
+	deadline, err := worm.Deadline(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline != "0x60a" {
+		t.Fatalf("deadline %s, want 0x60a", deadline)
+	}
+
+	seller1, _ := client.NewClient(sellerPriKey, "").SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline)
+	expired, _ := client.NewClient(buyerPriKey, "").SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x600", "")
+	_, err = worm.NFTDoesNotAuthorizeExchangesContext(ctx, expired, seller1, buyerAddress)
+	if !errors.Is(err, types.ErrOrderExpired) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderExpired)
+	}
+
+	buyer, _ := client.NewClient(buyerPriKey, "").SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline, "")
+	_, err = worm.NFTDoesNotAuthorizeExchangesContext(ctx, buyer, seller1, buyerAddress)
+	if !errors.Is(err, client.ErrPayloadRejected) {
+		t.Fatalf("err = %v, want the trade to be sent", err)
+	}
+
+	// the orders expire within the margin
+	worm.SetExpiryMargin(10)
+	_, err = worm.NFTDoesNotAuthorizeExchangesContext(ctx, buyer, seller1, buyerAddress)
+	if !errors.Is(err, types.ErrOrderExpired) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderExpired)
+	}
+}
+
+func TestOrderExpiryBoundary(t *testing.T) {
+	node := rejectingNode(t, "wormholes payload is wrong")
+	node.SetResult("eth_blockNumber", hexutil.Uint64(0x600))
+	worm := client.NewClient(exchangerPriKey, node.URL())
+	ctx := context.Background()
+	trade := func(blocks uint64) error {
+		deadline, err := worm.Deadline(ctx, blocks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seller1, _ := client.NewClient(sellerPriKey, "").SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline)
+		buyer, _ := client.NewClient(buyerPriKey, "").SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, deadline, "")
+		_, err = worm.NFTDoesNotAuthorizeExchangesContext(ctx, buyer, seller1, buyerAddress)
+		return err
+	}
+
+	// the orders valid before the next block can not be traded in it
+	if err := trade(1); !errors.Is(err, types.ErrOrderExpired) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderExpired)
+	}
+	if err := trade(2); !errors.Is(err, client.ErrPayloadRejected) {
+		t.Fatalf("err = %v, want the trade to be sent", err)
+	}
+
+	// without the expiry check the head is not read and the node rejects the orders
+	worm.SetExpiryCheck(false)
+	calls := node.Calls("eth_blockNumber")
+	if err := trade(1); !errors.Is(err, client.ErrPayloadRejected) {
+		t.Fatalf("err = %v, want the trade to be sent", err)
+	}
+	if n := node.Calls("eth_blockNumber"); n != calls+1 {
+		t.Fatalf("%d eth_blockNumber calls, want only the one of Deadline", n-calls)
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/follower"
+	"github.com/wormholes-org/wormholes-client/indexer"
+)
+
+func TestFollowerReorg(t *testing.T) {
+	node := newNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	sellerAccount, buyerAccount := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	nft1, nft2 := common.HexToAddress("0x01"), common.HexToAddress("0x02")
+
+	if _, err := seller.Mint(10, "/ipfs/ddfd90be9408b4", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seller.Mint(20, "/ipfs/dropped", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	checkpoint := filepath.Join(dir, "follower.json")
+	ix, err := indexer.Open(filepath.Join(dir, "index"), seller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ix.Close()
+	f, err := follower.New(seller, checkpoint, ix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetDepth(2)
+	if next, err := f.Sync(ctx); err != nil || next != 3 {
+		t.Fatalf("next block %d, %v, want 3", next, err)
+	}
+
+	// the block 2 is replaced by a transfer and the chain grows
+	node.Reorg(1)
+	if _, err := seller.Transfer(nft1.Hex(), buyerAddress); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := seller.Mint(30, "/ipfs/forked", ""); err != nil {
+		t.Fatal(err)
+	}
+	if next, err := f.Sync(ctx); err != nil || next != 4 {
+		t.Fatalf("next block %d, %v, want 4", next, err)
+	}
+	if nft, _ := ix.NFT(nft1); nft.Owner != buyerAccount || nft.Block != 2 {
+		t.Fatalf("nft %+v, want transferred to the buyer in block 2", nft)
+	}
+	if nft, _ := ix.NFT(nft2); nft.MetaURL != "/ipfs/forked" || nft.Block != 3 {
+		t.Fatalf("nft %+v, want minted in block 3", nft)
+	}
+	if created, _ := ix.ByCreator(sellerAccount); len(created) != 2 {
+		t.Fatalf("%d nfts created by the seller, want 2", len(created))
+	}
+
+	// a process stopped after the indexer applied a block but before the checkpoint
+	// was saved resumes from the checkpoint
+	node.Mine()
+	block, err := seller.BlockByNumber(ctx, big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ix.ApplyBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	f, err = follower.New(seller, checkpoint, ix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetDepth(2)
+	if f.Next() != 4 {
+		t.Fatalf("resumed at block %d, want 4", f.Next())
+	}
+	if next, err := f.Sync(ctx); err != nil || next != 5 {
+		t.Fatalf("next block %d, %v, want 5", next, err)
+	}
+
+	// only the last 2 blocks are remembered
+	node.Reorg(2)
+	node.Mine()
+	node.Mine()
+	node.Mine()
+	if _, err := f.Sync(ctx); !errors.Is(err, follower.ErrReorgTooDeep) {
+		t.Fatalf("err = %v, want %v", err, follower.ErrReorgTooDeep)
+	}
+	if f.Next() != 5 {
+		t.Fatalf("next block %d after the failed sync, want 5", f.Next())
+	}
+}
+
+// blockNumbers is a consumer recording the numbers of the applied blocks
+type blockNumbers struct {
+	applied chan uint64
+	numbers []uint64
+}
+
+func (b *blockNumbers) ApplyBlock(ctx context.Context, block *types.Block) error {
+	b.numbers = append(b.numbers, block.NumberU64())
+	b.applied <- block.NumberU64()
+	return nil
+}
+
+func (b *blockNumbers) Rollback(ctx context.Context, number uint64) error {
+	if uint64(len(b.numbers)) > number {
+		b.numbers = b.numbers[:number]
+	}
+	return nil
+}
+
+func TestFollowerRun(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	consumer := &blockNumbers{applied: make(chan uint64, 16)}
+	f, err := follower.New(worm, filepath.Join(t.TempDir(), "follower.json"), consumer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- f.Run(ctx)
+	}()
+
+	// the new heads are followed
+	for want := uint64(0); want < 3; want++ {
+		if want > 0 {
+			node.Mine()
+		}
+		select {
+		case number := <-consumer.applied:
+			if number != want {
+				t.Fatalf("block %d applied, want %d", number, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("block %d not applied", want)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFollowerSettings(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+	consumer := &blockNumbers{applied: make(chan uint64, 16)}
+	f, err := follower.New(worm, filepath.Join(t.TempDir(), "follower.json"), consumer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetPollInterval(10 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- f.Run(ctx)
+	}()
+
+	// the settings can be changed while the follower runs
+	f.SetLogger(nil)
+	f.SetPollInterval(time.Hour)
+	f.SetDepth(8)
+	node.Mine()
+	for want := uint64(0); want < 2; want++ {
+		select {
+		case number := <-consumer.applied:
+			if number != want {
+				t.Fatalf("block %d applied, want %d", number, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("block %d not applied", want)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestFollowerConnectionLost(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	consumer := &blockNumbers{applied: make(chan uint64, 16)}
+	f, err := follower.New(worm, filepath.Join(t.TempDir(), "follower.json"), consumer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.SetPollInterval(20 * time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go f.Run(ctx)
+
+	for want := uint64(0); want < 2; want++ {
+		if want == 1 {
+			// the block is mined while the subscription is lost, the polling finds it
+			for node.Subscriptions() != 1 {
+				time.Sleep(5 * time.Millisecond)
+			}
+			node.DropConnections()
+			node.Mine()
+		}
+		select {
+		case number := <-consumer.applied:
+			if number != want {
+				t.Fatalf("block %d applied, want %d", number, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("block %d not applied", want)
+		}
+	}
+}
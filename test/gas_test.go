@@ -0,0 +1,94 @@
+package test
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/mock"
+)
+
+func TestEstimateGas(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+
+	// the estimate of the node plus the default margin of 20%
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+	worm.SetGasMargin(0)
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+	// the default gas limit of Mint when the node can not estimate
+	node.SetError("eth_estimateGas", -32000, "execution reverted")
+	if _, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []uint64{mock.DefaultGasEstimate * 120 / 100, mock.DefaultGasEstimate, 60000}
+	for i, tx := range node.Transactions() {
+		if tx.Gas() != want[i] {
+			t.Fatalf("transaction %d: gas %d, want %d", i, tx.Gas(), want[i])
+		}
+	}
+}
+
+func TestDynamicFee(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetDynamicFee(true)
+
+	// without the London fork a legacy transaction is sent
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	node.SetBaseFee(big.NewInt(7))
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs := node.Transactions()
+	if txs[0].Type() != types.LegacyTxType || txs[0].GasPrice().Int64() != mock.DefaultGasPrice {
+		t.Fatalf("transaction type %d gas price %s, want a legacy transaction", txs[0].Type(), txs[0].GasPrice())
+	}
+	if txs[1].Type() != types.DynamicFeeTxType {
+		t.Fatalf("transaction type %d, want a dynamic fee transaction", txs[1].Type())
+	}
+	if txs[1].GasTipCap().Int64() != mock.DefaultGasPrice || txs[1].GasFeeCap().Int64() != mock.DefaultGasPrice+2*7 {
+		t.Fatalf("tip cap %s fee cap %s", txs[1].GasTipCap(), txs[1].GasFeeCap())
+	}
+}
+
+func TestEstimateGasRequest(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetGasMargin(50)
+
+	// the node estimates the transaction which is sent
+	var estimated []map[string]interface{}
+	node.Handle("eth_estimateGas", func(params []json.RawMessage) (interface{}, error) {
+		var msg map[string]interface{}
+		if err := mock.DecodeParams(params, &msg); err != nil {
+			return nil, err
+		}
+		estimated = append(estimated, msg)
+		return hexutil.Uint64(30000), nil
+	})
+	hash, err := worm.Transfer("0x0000000000000000000000000000000000000001", buyerAddress)
+	checkSent(t, node, hash, err, "Transfer")
+	tx := node.Transactions()[0]
+	if len(estimated) != 1 {
+		t.Fatalf("%d estimates, want 1", len(estimated))
+	}
+	if msg := estimated[0]; !strings.EqualFold(msg["to"].(string), buyerAddress) || msg["data"] != hexutil.Encode(tx.Data()) {
+		t.Fatalf("estimated %v, want the transfer to the buyer", msg)
+	}
+	if tx.Gas() != 45000 {
+		t.Fatalf("gas %d, want the estimate 30000 plus 50%%", tx.Gas())
+	}
+}
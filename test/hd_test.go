@@ -0,0 +1,67 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/wallet"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestHDWallet(t *testing.T) {
+	hd, err := wallet.NewHDWallet(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the well known first accounts of the test mnemonic
+	want := []string{
+		"0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		"0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0",
+	}
+	for i, address := range want {
+		signer, err := hd.Account(uint32(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer.Address() != common.HexToAddress(address) {
+			t.Fatalf("account %d is %s, want %s", i, signer.Address().Hex(), address)
+		}
+	}
+
+	signer, err := hd.Derive("m/44'/60'/0'/0/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.Address() != common.HexToAddress(want[1]) {
+		t.Fatalf("derived %s, want %s", signer.Address().Hex(), want[1])
+	}
+
+	worm, err := hd.NewClient(0, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.SignExchanger(exchangeAddress, exchangeAddress1, "0xa"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	mnemonic, err := wallet.NewMnemonic(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := len(strings.Fields(mnemonic)); words != 24 {
+		t.Fatalf("mnemonic has %d words, want 24", words)
+	}
+	if err := wallet.ValidateMnemonic(mnemonic); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.ValidateMnemonic(strings.Replace(testMnemonic, "about", "abandon", 1)); err == nil {
+		t.Fatal("expected a wrong checksum to be rejected")
+	}
+	if _, err := wallet.NewHDWallet("not a mnemonic", ""); err == nil {
+		t.Fatal("expected an invalid mnemonic to be rejected")
+	}
+}
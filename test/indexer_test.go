@@ -0,0 +1,238 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/indexer"
+	"github.com/wormholes-org/wormholes-client/types"
+)
+
+// addresses returns the addresses of nfts
+func addresses(nfts []*indexer.NFT) []common.Address {
+	var addresses []common.Address
+	for _, nft := range nfts {
+		addresses = append(addresses, nft.Address)
+	}
+	return addresses
+}
+
+func TestIndexer(t *testing.T) {
+	node, _ := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	buyer := client.NewClient(buyerPriKey, node.URL())
+	sellerAccount, buyerAccount := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	nft1 := "0x0000000000000000000000000000000000000001"
+	nft2 := "0x0000000000000000000000000000000000000002"
+
+	hash, err := seller.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("mint failed")
+	}
+	hash, err = seller.Mint(20, "/ipfs/qqqqqqqqqq", "")
+	if !mined(t, seller, hash, err) {
+		t.Fatal("mint failed")
+	}
+	hash, err = seller.Author(nft2, buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("author failed")
+	}
+	hash, err = seller.AccountAuthor(buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("account author failed")
+	}
+	hash, err = seller.Transfer(nft1, buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("transfer failed")
+	}
+	// failed transactions are not indexed
+	hash, err = seller.Transfer(nft1, sellerAddress)
+	if mined(t, seller, hash, err) {
+		t.Fatal("the former owner transferred the nft")
+	}
+	hash, err = seller.NormalTransaction(buyerAddress, 1, "")
+	if !mined(t, seller, hash, err) {
+		t.Fatal("normal transaction failed")
+	}
+
+	dir := filepath.Join(t.TempDir(), "index")
+	ix, err := indexer.Open(dir, buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := ix.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := buyer.BlockNumber(ctx)
+	if next != head+1 {
+		t.Fatalf("next block %d, want %d", next, head+1)
+	}
+
+	nft, err := ix.NFT(common.HexToAddress(nft1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nft.Owner != buyerAccount || nft.Creator != sellerAccount || nft.Exchanger != common.HexToAddress(exchangeAddress) ||
+		nft.Royalty != 10 || nft.MetaURL != "/ipfs/ddfd90be9408b4" || nft.Block != 5 {
+		t.Fatalf("nft %+v", nft)
+	}
+	nft, _ = ix.NFT(common.HexToAddress(nft2))
+	if nft.Owner != sellerAccount || nft.Approved != buyerAccount {
+		t.Fatalf("nft %+v", nft)
+	}
+	if _, err := ix.NFT(common.HexToAddress("0x03")); !errors.Is(err, ethereum.NotFound) {
+		t.Fatalf("err = %v, want %v", err, ethereum.NotFound)
+	}
+	approved, err := ix.Approvals(sellerAccount)
+	if err != nil || len(approved) != 1 || approved[0] != buyerAccount {
+		t.Fatalf("approvals %v, %v", approved, err)
+	}
+	ix.Close()
+
+	// the index is kept, the next blocks are added to it
+	deadline, err := seller.Deadline(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller2, _ := seller.SignSeller2("0x38D7EA4C68000", "0xa", "/ipfs/foundry", "0", "", deadline)
+	hash, err = buyer.FoundryTradeBuyer(seller2)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("foundry trade failed")
+	}
+	hash, err = buyer.SNFTToERB(nft1)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("snft to erb failed")
+	}
+	hash, err = seller.AccountAuthorRevoke(buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("account author revoke failed")
+	}
+
+	ix, err = indexer.Open(dir, buyer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ix.Close()
+	if _, err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	nft3 := common.HexToAddress("0x03")
+	owned, err := ix.ByOwner(buyerAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addresses(owned); len(got) != 1 || got[0] != nft3 {
+		t.Fatalf("nfts of the buyer %v, want %s", got, nft3)
+	}
+	if owned[0].Creator != sellerAccount || owned[0].Royalty != 10 || owned[0].MetaURL != "/ipfs/foundry" {
+		t.Fatalf("foundry nft %+v", owned[0])
+	}
+	created, _ := ix.ByCreator(sellerAccount)
+	if got := addresses(created); len(got) != 3 || got[0] != common.HexToAddress(nft1) || got[2] != nft3 {
+		t.Fatalf("nfts created by the seller %v", got)
+	}
+	exchanged, _ := ix.ByExchanger(common.HexToAddress(exchangeAddress))
+	if got := addresses(exchanged); len(got) != 1 || got[0] != common.HexToAddress(nft1) {
+		t.Fatalf("nfts of the exchanger %v", got)
+	}
+	if nft, _ := ix.NFT(common.HexToAddress(nft1)); nft.Owner != (common.Address{}) {
+		t.Fatalf("nft exchanged for erb owned by %s", nft.Owner)
+	}
+	if approved, _ := ix.Approvals(sellerAccount); len(approved) != 0 {
+		t.Fatalf("approvals %v after the revoke", approved)
+	}
+
+	// the blocks are indexed in order
+	block, _ := buyer.BlockByNumber(ctx, common.Big1)
+	if err := ix.ApplyBlock(ctx, block); err == nil {
+		t.Fatal("block 1 indexed twice")
+	}
+}
+
+func TestIndexerSNFT(t *testing.T) {
+	node, state := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	// the address of an SNFT may be shorter than an address
+	snftAddress := "0x80000000000000000000000000000000000004"
+	snft := &types.Account{}
+	snft.Owner = common.HexToAddress(sellerAddress)
+	state.SetAccount(common.HexToAddress(snftAddress), snft)
+
+	hash, err := seller.Transfer(snftAddress, buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("transfer failed")
+	}
+	ix, err := indexer.Open(filepath.Join(t.TempDir(), "index"), seller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ix.Close()
+	if _, err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	owned, err := ix.ByOwner(common.HexToAddress(buyerAddress))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addresses(owned); len(got) != 1 || got[0] != common.HexToAddress(snftAddress) {
+		t.Fatalf("nfts of the buyer %v, want %s", got, snftAddress)
+	}
+}
+
+func TestIndexerJournalDepth(t *testing.T) {
+	node, _ := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	send := func() {
+		t.Helper()
+		hash, err := seller.NormalTransaction(buyerAddress, 1, "")
+		if !mined(t, seller, hash, err) {
+			t.Fatal("normal transaction failed")
+		}
+	}
+	for i := 0; i < 4; i++ {
+		send()
+	}
+
+	dir := filepath.Join(t.TempDir(), "index")
+	ix, err := indexer.Open(dir, seller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix.SetJournalDepth(10)
+	if _, err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// the journals of all the blocks older than the lowered depth are deleted
+	ix.SetJournalDepth(2)
+	send()
+	if _, err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	ix.Close()
+
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	it := db.NewIterator(util.BytesPrefix([]byte("j")), nil)
+	defer it.Release()
+	journals := 0
+	for it.Next() {
+		journals++
+	}
+	if journals != 2 {
+		t.Fatalf("%d journals kept, want 2", journals)
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := tools.NewStdLogger(log.New(&buf, "", 0), tools.LevelInfo)
+
+	logger.Debug("hidden")
+	logger.Info("transaction sent", "op", "Mint", "hash", "0x01")
+	logger.Warn("gas estimation failed", "err", "execution reverted: no funds")
+	want := "level=INFO msg=\"transaction sent\" op=Mint hash=0x01\n" +
+		"level=WARN msg=\"gas estimation failed\" err=\"execution reverted: no funds\"\n"
+	if buf.String() != want {
+		t.Fatalf("got\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestClientLogger(t *testing.T) {
+	node := rejectingNode(t, "insufficient funds for gas * price + value")
+
+	var buf bytes.Buffer
+	worm := client.NewClient(priKey, node.URL())
+	worm.SetLogger(tools.NewStdLogger(log.New(&buf, "", 0), tools.LevelDebug))
+
+	seller1, err := worm.SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x65d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buyer, err := worm.SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x65d", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worm.NFTDoesNotAuthorizeExchanges(buyer, seller1, sellerAddress); err == nil {
+		t.Fatal("expected the transaction to be rejected")
+	}
+	out := buf.String()
+	if !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "op=NFTDoesNotAuthorizeExchanges") {
+		t.Fatalf("missing the rejection in the log:\n%s", out)
+	}
+	if strings.Contains(out, "wormholes:") || strings.Contains(out, "sig") {
+		t.Fatalf("the log leaks the payload:\n%s", out)
+	}
+}
+
+func TestToolsLogger(t *testing.T) {
+	defer tools.SetLogger(nil)
+	sig := make([]byte, 65)
+	sig[64] = 27
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			tools.SetLogger(tools.NewStdLogger(log.New(&bytes.Buffer{}, "", 0), tools.LevelDebug))
+		}()
+		go func() {
+			defer wg.Done()
+			tools.RecoverAddress("order", hexutil.Encode(sig))
+		}()
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	tools.SetLogger(tools.NewStdLogger(log.New(&buf, "", 0), tools.LevelDebug))
+	tools.RecoverAddress("order", hexutil.Encode(sig))
+	if !strings.Contains(buf.String(), "msg=\"recover address\"") {
+		t.Fatalf("missing the recovery in the log:\n%s", buf.String())
+	}
+}
+
+func TestClientDialFailure(t *testing.T) {
+	worm := client.NewClient(priKey, "ws://127.0.0.1:1")
+	var buf bytes.Buffer
+	worm.SetLogger(tools.NewStdLogger(log.New(&buf, "", 0), tools.LevelInfo))
+	if !strings.Contains(buf.String(), "msg=\"failed to connect to the node\"") {
+		t.Fatalf("missing the dial failure in the log:\n%s", buf.String())
+	}
+
+	_, err := worm.BlockNumber(context.Background())
+	if !errors.Is(err, client.ErrTransport) || !strings.Contains(err.Error(), "127.0.0.1:1") {
+		t.Fatalf("err = %v, want the dial failure", err)
+	}
+	// the client still signs
+	if _, err := worm.SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x65d"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,100 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/types"
+)
+
+func TestMatchPrices(t *testing.T) {
+	seller := client.NewClient(sellerPriKey, "")
+	buyer := client.NewClient(buyerPriKey, "")
+	builder := &client.TxBuilder{GasPrice: big.NewInt(1)}
+
+	seller2, _ := seller.SignSeller2("0x9", "0xa", "/ipfs/qqqqqqqqqq", "0", exchangeAddress, "0x703")
+	higher, _ := buyer.SignBuyer("0x10", "", exchangeAddress, "0x703", sellerAddress)
+	lower, _ := buyer.SignBuyer("0x8", "", exchangeAddress, "0x703", sellerAddress)
+
+	// "0x10" sorts before "0x9" as a string
+	tx, err := builder.FoundryExchange(higher, seller2, buyerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value().Int64() != 16 {
+		t.Fatalf("value %s, want 16", tx.Value())
+	}
+	_, err = builder.FoundryExchange(lower, seller2, buyerAddress)
+	if !errors.Is(err, types.ErrOrderMismatch) || !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderMismatch)
+	}
+}
+
+func TestMatchOrders(t *testing.T) {
+	seller := client.NewClient(sellerPriKey, "")
+	buyer := client.NewClient(buyerPriKey, "")
+	exchanger := client.NewClient(exchangerPriKey, "")
+	builder := &client.TxBuilder{GasPrice: big.NewInt(1)}
+
+	nft := "0x0000000000000000000000000000000000000003"
+	seller1, _ := seller.SignSeller1("0x38D7EA4C68000", nft, exchangeAddress, "0x65d")
+	auth, _ := exchanger.SignExchanger(exchangeAddress, exchangeAddress1, "0x65d")
+	otherAuth, _ := buyer.SignExchanger(buyerAddress, exchangeAddress1, "0x65d")
+
+	tests := []struct {
+		name  string
+		buyer []byte
+		auth  []byte
+	}{
+		{"other exchanger", signBuyer(t, buyer, "0x38D7EA4C68000", nft, exchangeAddress1, "0x65d", sellerAddress), auth},
+		{"other nft", signBuyer(t, buyer, "0x38D7EA4C68000", "0x0000000000000000000000000000000000000004", exchangeAddress, "0x65d", sellerAddress), auth},
+		{"other authorizer", signBuyer(t, buyer, "0x38D7EA4C68000", nft, exchangeAddress, "0x65d", sellerAddress), otherAuth},
+	}
+	for _, test := range tests {
+		_, err := builder.NftExchangeMatch(test.buyer, seller1, test.auth, buyerAddress)
+		if !errors.Is(err, types.ErrOrderMismatch) {
+			t.Fatalf("%s: err = %v, want %v", test.name, err, types.ErrOrderMismatch)
+		}
+	}
+
+	// the builder does not recover the signature of the seller order, MatchOrders does
+	otherSeller := signBuyer(t, buyer, "0x38D7EA4C68000", nft, exchangeAddress, "0x65d", buyerAddress)
+	tx, err := builder.NftExchangeMatch(otherSeller, seller1, auth, buyerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := client.DecodeData(tx.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := types.MatchOrders(payload, 0); !errors.Is(err, types.ErrOrderMismatch) {
+		t.Fatalf("other seller: err = %v, want %v", err, types.ErrOrderMismatch)
+	}
+
+	matching := signBuyer(t, buyer, "0x38D7EA4C68000", nft, exchangeAddress, "0x65d", sellerAddress)
+	tx, err = builder.NftExchangeMatch(matching, seller1, auth, buyerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err = client.DecodeData(tx.Data())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := types.MatchOrders(payload, 0x65c); err != nil {
+		t.Fatal(err)
+	}
+	// the orders are valid before their block number
+	if err := types.MatchOrders(payload, 0x65d); !errors.Is(err, types.ErrOrderExpired) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderExpired)
+	}
+}
+
+func signBuyer(t *testing.T, worm *client.Wormholes, amount, nftAddress, exchanger, blockNumber, seller string) []byte {
+	order, err := worm.SignBuyer(amount, nftAddress, exchanger, blockNumber, seller)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
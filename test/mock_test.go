@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/mock"
+)
+
+// newNode starts a mock node which is closed at the end of the test
+func newNode(t *testing.T) *mock.Node {
+	t.Helper()
+	node := mock.NewNode()
+	t.Cleanup(node.Close)
+	return node
+}
+
+// checkSent checks that the transaction hash was sent to node as the wormholes
+// operation op, and returns it decoded
+func checkSent(t *testing.T, node *mock.Node, hash string, err error, op string) *client.DecodedTransaction {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := node.Transactions()
+	if len(txs) == 0 {
+		t.Fatal("no transaction sent")
+	}
+	decoded, err := client.DecodeTransaction(txs[len(txs)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash != common.HexToHash(hash) {
+		t.Fatalf("hash %s, want %s", hash, decoded.Hash)
+	}
+	if decoded.Operation != op {
+		t.Fatalf("operation %s, want %s", decoded.Operation, op)
+	}
+	return decoded
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wormholes-org/wormholes-client/client"
+)
+
+func TestConcurrentNonces(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+
+	const senders = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, senders)
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var nonces []int
+	for _, tx := range node.Transactions() {
+		nonces = append(nonces, int(tx.Nonce()))
+	}
+	sort.Ints(nonces)
+	if len(nonces) != senders {
+		t.Fatalf("%d transactions sent, want %d", len(nonces), senders)
+	}
+	for i, nonce := range nonces {
+		if nonce != i {
+			t.Fatalf("nonces %v, want 0 to %d", nonces, senders-1)
+		}
+	}
+	// the nonce is read from the node once
+	if node.Calls("eth_getTransactionCount") != 1 {
+		t.Fatalf("%d nonce requests, want 1", node.Calls("eth_getTransactionCount"))
+	}
+}
+
+func TestNonceConflict(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+	other := client.NewClient(priKey, node.URL())
+
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	// another client of the account uses the nonce counted by worm
+	if _, err := other.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	// the node rejects it, worm resyncs the nonce and sends again
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs := node.Transactions()
+	if len(txs) != 3 || txs[2].Nonce() != 2 {
+		t.Fatalf("%d transactions, last nonce %d, want 3 with the last nonce 2", len(txs), txs[len(txs)-1].Nonce())
+	}
+}
+
+func TestNonceRelease(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	// the nonce of a transaction rejected by the node is handed out again
+	send := node.Handler("eth_sendRawTransaction")
+	node.SetError("eth_sendRawTransaction", -32000, "insufficient funds for gas * price + value")
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want %v", err, client.ErrInsufficientFunds)
+	}
+	node.Handle("eth_sendRawTransaction", send)
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+
+	txs := node.Transactions()
+	if len(txs) != 2 || txs[1].Nonce() != 1 {
+		t.Fatalf("%d transactions, last nonce %d, want 2 with the last nonce 1", len(txs), txs[len(txs)-1].Nonce())
+	}
+	if node.Calls("eth_getTransactionCount") != 1 {
+		t.Fatalf("%d nonce requests, want 1", node.Calls("eth_getTransactionCount"))
+	}
+}
+
+func TestNonceReplacementUnderpriced(t *testing.T) {
+	node := newNode(t)
+	send := node.Handler("eth_sendRawTransaction")
+	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		result, err := send(params)
+		if err != nil && strings.Contains(err.Error(), "nonce too low") {
+			return nil, errors.New("replacement transaction underpriced")
+		}
+		return result, err
+	})
+	worm := client.NewClient(priKey, node.URL())
+	other := client.NewClient(priKey, node.URL())
+
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	// the nonce counted by worm is held by a pending transaction, worm resyncs
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	txs := node.Transactions()
+	if len(txs) != 3 || txs[2].Nonce() != 2 {
+		t.Fatalf("%d transactions, last nonce %d, want 3 with the last nonce 2", len(txs), txs[len(txs)-1].Nonce())
+	}
+}
+
+func TestNonceAfterTimeout(t *testing.T) {
+	node := newNode(t)
+	send := node.Handler("eth_sendRawTransaction")
+	worm := client.NewClient(priKey, node.URL())
+
+	// the node receives the transaction but answers after the deadline
+	node.Handle("eth_sendRawTransaction", func(params []json.RawMessage) (interface{}, error) {
+		result, err := send(params)
+		time.Sleep(200 * time.Millisecond)
+		return result, err
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := worm.SNFTToERBContext(ctx, "0x8000000000000000000000000000000000000004"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	node.Handle("eth_sendRawTransaction", send)
+
+	// the nonce may be held by the node, it is read again instead of reused
+	if _, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004"); err != nil {
+		t.Fatal(err)
+	}
+	txs := node.Transactions()
+	if len(txs) != 2 || txs[1].Nonce() != 1 {
+		t.Fatalf("%d transactions, last nonce %d, want 2 with the last nonce 1", len(txs), txs[len(txs)-1].Nonce())
+	}
+	// the held nonce is not sent again
+	if node.Calls("eth_sendRawTransaction") != 2 || node.Calls("eth_getTransactionCount") != 2 {
+		t.Fatalf("%d transactions sent and %d nonce requests, want 2 and 2", node.Calls("eth_sendRawTransaction"), node.Calls("eth_getTransactionCount"))
+	}
+}
@@ -2,28 +2,122 @@ package test
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"github.com/wormholes-org/wormholes-client/client"
-	"math/rand"
+	"math/big"
 	"testing"
-	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/types"
 )
 
 func TestGetAccountInfo(t *testing.T) {
-	worm := client.NewClient("http://192.168.4.237:8574", "7c6786275d6011adb6288587757653d3f9061275bafc2c35ae62efe0bc4973e9")
+	node := newNode(t)
+	nftAddress := common.HexToAddress("0x8000000000000000000000000000000000000004")
+	node.SetResult("eth_getBlockBeneficiaryAddressByNumber", types.BeneficiaryAddressList{
+		{Address: common.HexToAddress(exchangeAddress), NftAddress: nftAddress},
+	})
+	node.SetResult("eth_getAccountInfo", &types.Account{
+		Balance:    big.NewInt(0),
+		AccountNFT: types.AccountNFT{Owner: common.HexToAddress(exchangeAddress), MetaURL: "/ipfs/ddfd90be9408b4"},
+	})
+
+	worm := client.NewClient(priKey, node.URL())
+	ctx := context.Background()
+	blockNumber, err := worm.BlockNumber(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := worm.GetBlockBeneficiaryAddressByNumber(ctx, int64(blockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*rs) != 1 || (*rs)[0].NftAddress != nftAddress {
+		t.Fatalf("beneficiaries %v", *rs)
+	}
+	account, err := worm.GetAccountInfo(ctx, (*rs)[0].NftAddress.String(), int64(blockNumber))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Owner != common.HexToAddress(exchangeAddress) || account.MetaURL != "/ipfs/ddfd90be9408b4" {
+		t.Fatalf("account %+v", account)
+	}
+}
+
+func TestValidatorQueries(t *testing.T) {
+	node := newNode(t)
+	validator := common.HexToAddress(exchangeAddress)
+	node.SetResult("eth_getValidator", &types.ValidatorList{Validators: []*types.Validator{{Addr: validator, Balance: big.NewInt(7)}}})
+	node.SetResult("erb_getValidators", []common.Address{validator})
+	node.SetResult("erb_getElevenValidatorsWithProxy", []common.Address{validator})
+	node.SetResult("erb_getRealAddr", validator)
+	node.SetResult("eth_queryMinerProxy", types.MinerProxyList{{Address: validator, Proxy: common.HexToAddress(buyerAddress)}})
+	node.SetResult("erb_getCoefficientByNumber", []*types.BlockParticipants{{Address: validator, Coefficient: 70}})
+
+	worm := client.NewClient(priKey, node.URL())
+	ctx := context.Background()
+	validators, err := worm.GetValidators(ctx, 0)
+	if err != nil || len(validators.Validators) != 1 || validators.Validators[0].Addr != validator {
+		t.Fatalf("validators %v, err %v", validators, err)
+	}
+	withoutProxy, err := worm.GetRandom11ValidatorsWithOutProxy(ctx, 0)
+	if err != nil || len(withoutProxy) != 1 || withoutProxy[0] != validator {
+		t.Fatalf("validators %v, err %v", withoutProxy, err)
+	}
+	withProxy, err := worm.GetRandom11ValidatorsWithProxy(ctx, 0)
+	if err != nil || len(withProxy) != 1 {
+		t.Fatalf("validators %v, err %v", withProxy, err)
+	}
+	real, err := worm.GetRealAddr(ctx, common.HexToAddress(buyerAddress))
+	if err != nil || real != validator {
+		t.Fatalf("real address %s, err %v", real, err)
+	}
+	proxies, err := worm.QueryMinerProxy(ctx, 0, exchangeAddress)
+	if err != nil || len(proxies) != 1 || proxies[0].Proxy != common.HexToAddress(buyerAddress) {
+		t.Fatalf("proxies %v, err %v", proxies, err)
+	}
+	coefficients, err := worm.GetCoefficientByNumber(ctx, 0)
+	if err != nil || len(coefficients) != 1 || coefficients[0].Coefficient != 70 {
+		t.Fatalf("coefficients %v, err %v", coefficients, err)
+	}
+	if node.Calls("eth_getValidator") != 1 {
+		t.Fatalf("%d eth_getValidator requests, want 1", node.Calls("eth_getValidator"))
+	}
+}
+
+func TestBlockTransactions(t *testing.T) {
+	node := newNode(t)
+	node.SetAutoMine(false)
+	worm := client.NewClient(priKey, node.URL())
+	hash1, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash2, err := worm.SNFTPledge("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined := node.Mine()
+
 	ctx := context.Background()
-	blockNumber, _ := worm.BlockNumber(ctx)
-	fmt.Println("blockNumber ", blockNumber)
-	rs, _ := worm.GetBlockBeneficiaryAddressByNumber(ctx, int64(blockNumber))
-	rss1, _ := json.Marshal(*rs)
-	fmt.Println(string(rss1))
-
-	rand.Seed(time.Now().UnixNano())
-	n := rand.Intn(len(*rs))
-	fmt.Println("Address", (*rs)[n].Address.String())
-	fmt.Println("NFTAddress", (*rs)[n].NftAddress.String())
-	rs1, _ := worm.GetAccountInfo(ctx, (*rs)[n].NftAddress.String(), int64(blockNumber))
-	rss1, _ = json.Marshal(*rs1)
-	fmt.Println(string(rss1))
+	block, err := worm.BlockByNumber(ctx, mined.Number())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != mined.Hash() || len(block.Transactions()) != 2 {
+		t.Fatalf("block %s with %d transactions, want %s with 2", block.Hash(), len(block.Transactions()), mined.Hash())
+	}
+	decoded, err := client.DecodeTransaction(block.Transactions()[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Hash != common.HexToHash(hash2) || decoded.Operation != "SNFTPledge" {
+		t.Fatalf("decoded %s %s, want the pledge %s", decoded.Operation, decoded.Hash, hash2)
+	}
+	tx, err := worm.TransactionInBlock(ctx, block.Hash(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Hash() != common.HexToHash(hash1) {
+		t.Fatalf("transaction %s, want %s", tx.Hash(), hash1)
+	}
 }
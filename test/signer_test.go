@@ -0,0 +1,68 @@
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+)
+
+// countingSigner is a Signer implemented outside of the client package
+type countingSigner struct {
+	client.Signer
+	signs int
+}
+
+func (s *countingSigner) SignHash(hash []byte) ([]byte, error) {
+	s.signs++
+	return s.Signer.SignHash(hash)
+}
+
+func TestCustomSigner(t *testing.T) {
+	key, err := client.NewKeySigner("0x" + buyerPriKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Address() != common.HexToAddress(buyerAddress) {
+		t.Fatalf("address %s, want %s", key.Address(), buyerAddress)
+	}
+	signer := &countingSigner{Signer: key}
+	worm, err := client.NewClientWithSigner(signer, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buyer, err := worm.SignBuyer("0xde0b6b3a7640000", "0x0000000000000000000000000000000000000002", exchangeAddress, "0x487", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.signs != 1 {
+		t.Fatalf("signer used %d times, want 1", signer.signs)
+	}
+	// the raw key wallet produces the same order
+	want, _ := client.NewClient(buyerPriKey, "").SignBuyer("0xde0b6b3a7640000", "0x0000000000000000000000000000000000000002", exchangeAddress, "0x487", "")
+	if !bytes.Equal(buyer, want) {
+		t.Fatalf("buyer %s, want %s", buyer, want)
+	}
+
+	chainID := big.NewInt(51888)
+	tx := types.NewTransaction(0, common.HexToAddress(sellerAddress), big.NewInt(0), 21000, big.NewInt(1), nil)
+	signedTx, err := worm.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, _ := types.Sender(types.LatestSignerForChainID(chainID), signedTx)
+	if sender != signer.Address() {
+		t.Fatalf("sender %s, want %s", sender, signer.Address())
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	worm := client.NewClient("not a key", "")
+	if _, err := worm.SignSeller1("0x1", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x677"); err == nil {
+		t.Fatal("expected signing with an invalid key to fail")
+	}
+}
@@ -0,0 +1,246 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/mock"
+	"github.com/wormholes-org/wormholes-client/unit"
+)
+
+// newStateNode starts a mock node executing the transactions against a simulated
+// state in which the accounts of the tests own 1000 ERB
+func newStateNode(t *testing.T) (*mock.Node, *mock.State) {
+	node := newNode(t)
+	state := mock.NewState()
+	node.SetState(state)
+	for _, account := range []string{buyerAddress, sellerAddress, exchangeAddress, exchangeAddress1} {
+		node.SetBalance(common.HexToAddress(account), unit.ToWei(1000, unit.ERB))
+	}
+	return node, state
+}
+
+// mined waits for the transaction hash and returns whether it succeeded
+func mined(t *testing.T, worm *client.Wormholes, hash string, err error) bool {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := worm.WaitMined(context.Background(), hash, client.WaitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Successful()
+}
+
+func TestStateTrade(t *testing.T) {
+	node, state := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	buyer := client.NewClient(buyerPriKey, node.URL())
+	exchanger := client.NewClient(exchangerPriKey, node.URL())
+
+	hash, err := seller.Mint(10, "/ipfs/ddfd90be9408b4", exchangeAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("mint failed")
+	}
+	nftAddress := "0x0000000000000000000000000000000000000001"
+	nft, err := seller.GetAccountInfo(ctx, nftAddress, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nft.Owner != common.HexToAddress(sellerAddress) || nft.Creator != nft.Owner || nft.MetaURL != "/ipfs/ddfd90be9408b4" {
+		t.Fatalf("minted nft %+v", nft.AccountNFT)
+	}
+
+	// only an exchanger can match the orders
+	deadline, err := exchanger.Deadline(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller1, _ := seller.SignSeller1("0xde0b6b3a7640000", nftAddress, exchangeAddress, deadline)
+	order, _ := buyer.SignBuyer("0xde0b6b3a7640000", nftAddress, exchangeAddress, deadline, "")
+	hash, err = exchanger.NFTDoesNotAuthorizeExchanges(order, seller1, buyerAddress)
+	if mined(t, exchanger, hash, err) {
+		t.Fatal("a trade was matched by an account which is not an exchanger")
+	}
+
+	hash, err = exchanger.Open(10, "wormholes", "www.kang123456.com")
+	if !mined(t, exchanger, hash, err) {
+		t.Fatal("open failed")
+	}
+	account, _ := exchanger.GetAccountInfo(ctx, exchangeAddress, 0)
+	if !account.ExchangerFlag || account.ExchangerBalance.Cmp(unit.ToWei(100, unit.ERB)) != 0 || account.ExchangerName != "wormholes" {
+		t.Fatalf("exchanger %+v", account)
+	}
+
+	sellerBalance := state.Balance(common.HexToAddress(sellerAddress))
+	buyerBalance := state.Balance(common.HexToAddress(buyerAddress))
+	hash, err = exchanger.NFTDoesNotAuthorizeExchanges(order, seller1, buyerAddress)
+	if !mined(t, exchanger, hash, err) {
+		t.Fatal("trade failed")
+	}
+	nft, _ = buyer.GetAccountInfo(ctx, nftAddress, 0)
+	if nft.Owner != common.HexToAddress(buyerAddress) {
+		t.Fatalf("nft owned by %s, want the buyer", nft.Owner)
+	}
+	price := unit.ToWei(1, unit.ERB)
+	if got := state.Balance(common.HexToAddress(sellerAddress)); got.Cmp(new(big.Int).Add(sellerBalance, price)) != 0 {
+		t.Fatalf("seller balance %s, want %s more than %s", got, price, sellerBalance)
+	}
+	if got := state.Balance(common.HexToAddress(buyerAddress)); got.Cmp(new(big.Int).Sub(buyerBalance, price)) != 0 {
+		t.Fatalf("buyer balance %s, want %s less than %s", got, price, buyerBalance)
+	}
+
+	// the seller does not own the nft anymore
+	hash, err = seller.Transfer(nftAddress, sellerAddress)
+	if mined(t, seller, hash, err) {
+		t.Fatal("the former owner transferred the nft")
+	}
+	_, err = seller.DryRun().Transfer(nftAddress, sellerAddress)
+	if !errors.Is(err, client.ErrPayloadRejected) {
+		t.Fatalf("err = %v, want %v", err, client.ErrPayloadRejected)
+	}
+
+	hash, err = exchanger.Close()
+	if !mined(t, exchanger, hash, err) {
+		t.Fatal("close failed")
+	}
+	account, _ = exchanger.GetAccountInfo(ctx, exchangeAddress, 0)
+	if account.ExchangerFlag || account.ExchangerBalance.Sign() != 0 {
+		t.Fatalf("exchanger %+v", account)
+	}
+}
+
+func TestStateAuthorAndPledge(t *testing.T) {
+	node, _ := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	buyer := client.NewClient(buyerPriKey, node.URL())
+
+	hash, err := seller.Mint(10, "/ipfs/ddfd90be9408b4", "")
+	if !mined(t, seller, hash, err) {
+		t.Fatal("mint failed")
+	}
+	nftAddress := "0x0000000000000000000000000000000000000001"
+	hash, err = seller.Author(nftAddress, buyerAddress)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("author failed")
+	}
+	nft, _ := seller.GetAccountInfo(ctx, nftAddress, 0)
+	if nft.NFTApproveAddressList != common.HexToAddress(buyerAddress) {
+		t.Fatalf("nft approved to %s, want the buyer", nft.NFTApproveAddressList)
+	}
+	// the approved account can transfer the nft
+	hash, err = buyer.Transfer(nftAddress, buyerAddress)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("transfer by the approved account failed")
+	}
+	nft, _ = seller.GetAccountInfo(ctx, nftAddress, 0)
+	if nft.Owner != common.HexToAddress(buyerAddress) {
+		t.Fatalf("nft owned by %s, want the buyer", nft.Owner)
+	}
+
+	hash, err = buyer.TokenPledge([]byte(""), "", 10)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("pledge failed")
+	}
+	account, _ := buyer.GetAccountInfo(ctx, buyerAddress, 0)
+	if account.PledgedBalance.Cmp(unit.ToWei(10, unit.ERB)) != 0 || account.NFTBalance != 1 {
+		t.Fatalf("account %+v", account)
+	}
+	// more than the pledge can not be revoked
+	hash, err = buyer.TokenRevokesPledge(11)
+	if mined(t, buyer, hash, err) {
+		t.Fatal("revoked more than the pledge")
+	}
+
+	// the node rejects transactions the sender can not pay
+	_, err = buyer.NormalTransaction(sellerAddress, 1000, "")
+	if !errors.Is(err, client.ErrInsufficientFunds) {
+		t.Fatalf("err = %v, want %v", err, client.ErrInsufficientFunds)
+	}
+}
+
+func TestStateValidators(t *testing.T) {
+	node, state := newStateNode(t)
+	ctx := context.Background()
+	seller := client.NewClient(sellerPriKey, node.URL())
+	buyer := client.NewClient(buyerPriKey, node.URL())
+	sellerAccount, buyerAccount := common.HexToAddress(sellerAddress), common.HexToAddress(buyerAddress)
+	proxy := common.HexToAddress(exchangeAddress1)
+
+	// no account pledged yet
+	if validators, err := seller.GetValidators(ctx, 0); err != nil || len(validators.Validators) != 0 {
+		t.Fatalf("validators %v, err %v", validators, err)
+	}
+
+	hash, err := seller.TokenPledge([]byte(""), "", 10)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("pledge failed")
+	}
+	proxySign, _ := buyer.SignDelegate(exchangeAddress1, buyerAddress)
+	hash, err = buyer.TokenPledge(proxySign, exchangeAddress1, 20)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("pledge failed")
+	}
+	coefficient := state.Account(buyerAccount)
+	coefficient.Coefficient = 70
+	state.SetAccount(buyerAccount, coefficient)
+
+	validators, err := seller.GetValidators(ctx, 0)
+	if err != nil || len(validators.Validators) != 2 {
+		t.Fatalf("validators %v, err %v", validators, err)
+	}
+	// the validators are in the order of their addresses
+	first, second := validators.Validators[0], validators.Validators[1]
+	if first.Addr != buyerAccount || first.Proxy != proxy || first.Balance.Cmp(unit.ToWei(20, unit.ERB)) != 0 {
+		t.Fatalf("validator %+v", first)
+	}
+	if second.Addr != sellerAccount || second.Proxy != (common.Address{}) {
+		t.Fatalf("validator %+v", second)
+	}
+	withoutProxy, err := seller.GetRandom11ValidatorsWithOutProxy(ctx, 0)
+	if err != nil || len(withoutProxy) != 2 || withoutProxy[0] != buyerAccount {
+		t.Fatalf("validators %v, err %v", withoutProxy, err)
+	}
+	withProxy, err := seller.GetRandom11ValidatorsWithProxy(ctx, 0)
+	if err != nil || len(withProxy) != 2 || withProxy[0] != proxy || withProxy[1] != sellerAccount {
+		t.Fatalf("validators %v, err %v", withProxy, err)
+	}
+	if real, err := seller.GetRealAddr(ctx, proxy); err != nil || real != buyerAccount {
+		t.Fatalf("real address %s, err %v", real, err)
+	}
+	proxies, err := seller.QueryMinerProxy(ctx, 0, buyerAddress)
+	if err != nil || len(proxies) != 1 || proxies[0].Proxy != proxy {
+		t.Fatalf("proxies %v, err %v", proxies, err)
+	}
+	coefficients, err := seller.GetCoefficientByNumber(ctx, 0)
+	if err != nil || len(coefficients) != 2 || coefficients[0].Coefficient != 70 {
+		t.Fatalf("coefficients %v, err %v", coefficients, err)
+	}
+	beneficiaries, err := seller.GetBlockBeneficiaryAddressByNumber(ctx, 0)
+	if err != nil || len(*beneficiaries) != 2 || (*beneficiaries)[1].Address != sellerAccount {
+		t.Fatalf("beneficiaries %v, err %v", beneficiaries, err)
+	}
+
+	// the seller is not a validator anymore after revoking its pledge, the buyer
+	// delegates to another proxy
+	hash, err = seller.TokenRevokesPledge(10)
+	if !mined(t, seller, hash, err) {
+		t.Fatal("revoke failed")
+	}
+	proxySign, _ = buyer.SignDelegate(exchangeAddress, buyerAddress)
+	hash, err = buyer.AccountDelegate(proxySign, exchangeAddress)
+	if !mined(t, buyer, hash, err) {
+		t.Fatal("delegate failed")
+	}
+	withProxy, err = seller.GetRandom11ValidatorsWithProxy(ctx, 0)
+	if err != nil || len(withProxy) != 1 || withProxy[0] != common.HexToAddress(exchangeAddress) {
+		t.Fatalf("validators %v, err %v", withProxy, err)
+	}
+}
@@ -0,0 +1,167 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/wormholes-org/wormholes-client/client"
+)
+
+// newHead returns the next header of heads
+func newHead(t *testing.T, heads <-chan *types.Header) *types.Header {
+	t.Helper()
+	select {
+	case head := <-heads:
+		return head
+	case <-time.After(time.Second):
+		t.Fatal("no new head")
+	}
+	return nil
+}
+
+func TestSubscribeNewHead(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	ctx := context.Background()
+
+	heads := make(chan *types.Header, 16)
+	sub, err := worm.SubscribeNewHead(ctx, heads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+	pending := make(chan common.Hash, 16)
+	pendingSub, err := worm.SubscribeNewPendingTransactions(ctx, pending)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pendingSub.Unsubscribe()
+
+	hash, err := worm.Mint(10, "/ipfs/ddfd90be9408b4", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-pending:
+		if got != common.HexToHash(hash) {
+			t.Fatalf("pending transaction %s, want %s", got, hash)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no pending transaction")
+	}
+	head := newHead(t, heads)
+	block, err := worm.BlockByNumber(ctx, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != block.Hash() {
+		t.Fatalf("head %d %s, want the block 1 %s", head.Number, head.Hash(), block.Hash())
+	}
+
+	// WaitMined is woken up by the new heads
+	node.SetAutoMine(false)
+	hash, err = worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		node.Mine()
+	}()
+	result, err := worm.WaitMined(ctx, hash, client.WaitOptions{Timeout: 5 * time.Second, PollInterval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BlockNumber != 2 {
+		t.Fatalf("mined in block %d, want 2", result.BlockNumber)
+	}
+}
+
+func TestSubscribeOverHTTP(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.URL())
+	_, err := worm.SubscribeNewHead(context.Background(), make(chan *types.Header))
+	if !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		t.Fatalf("err = %v, want %v", err, rpc.ErrNotificationsUnsupported)
+	}
+}
+
+func TestResubscribe(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	worm.SetResubscribeBackoff(50 * time.Millisecond)
+
+	heads := make(chan *types.Header, 16)
+	sub, err := worm.SubscribeNewHead(context.Background(), heads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	node.DropConnections()
+	deadline := time.Now().Add(5 * time.Second)
+	for node.Subscriptions() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("not subscribed again")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	block := node.Mine()
+	if head := newHead(t, heads); head.Hash() != block.Hash() {
+		t.Fatalf("head %s, want %s", head.Hash(), block.Hash())
+	}
+
+	sub.Unsubscribe()
+	if _, ok := <-sub.Err(); ok {
+		t.Fatal("the error channel is not closed by Unsubscribe")
+	}
+}
+
+func TestSubscribeFilterLogs(t *testing.T) {
+	node := newNode(t)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+
+	address := common.HexToAddress(exchangeAddress)
+	topic := common.HexToHash("0x01")
+	logs := make(chan types.Log, 16)
+	sub, err := worm.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{
+		Addresses: []common.Address{address},
+		Topics:    [][]common.Hash{{topic}},
+	}, logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	node.EmitLog(&types.Log{Address: common.HexToAddress(buyerAddress), Topics: []common.Hash{topic}})
+	node.EmitLog(&types.Log{Address: address, Topics: []common.Hash{common.HexToHash("0x02")}})
+	node.EmitLog(&types.Log{Address: address, Topics: []common.Hash{topic}, Data: []byte{1}})
+	select {
+	case log := <-logs:
+		if log.Address != address || len(log.Data) != 1 {
+			t.Fatalf("log %+v", log)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no log")
+	}
+	select {
+	case log := <-logs:
+		t.Fatalf("unexpected log %+v", log)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err = worm.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{BlockHash: &topic, FromBlock: common.Big1}, logs)
+	if !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, client.ErrValidation)
+	}
+}
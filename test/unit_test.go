@@ -0,0 +1,82 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/unit"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		wei    string
+	}{
+		{"1.25 ERB", "1250000000000000000"},
+		{"0.5erb", "500000000000000000"},
+		{"30 gwei", "30000000000"},
+		{"1.5 GWei", "1500000000"},
+		{"1000 wei", "1000"},
+		{"10000000000000000000 ERB", "10000000000000000000000000000000000000"},
+		{".1 ERB", "100000000000000000"},
+	}
+	for _, test := range tests {
+		wei, err := unit.Parse(test.amount)
+		if err != nil {
+			t.Fatalf("%s: %v", test.amount, err)
+		}
+		if wei.String() != test.wei {
+			t.Fatalf("%s is %s wei, want %s", test.amount, wei, test.wei)
+		}
+	}
+
+	for _, amount := range []string{"1.25", "1.5 wei", "-1 ERB", "0.0000000000000000001 ERB", "1,5 ERB", ". ERB", "1e18 wei"} {
+		if _, err := unit.Parse(amount); err == nil {
+			t.Fatalf("expected %q to be rejected", amount)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	wei, _ := new(big.Int).SetString("1250000000000000000", 10)
+	if s := unit.Format(wei, unit.ERB); s != "1.25 ERB" {
+		t.Fatalf("got %s", s)
+	}
+	if s := unit.FormatUnit(big.NewInt(1), unit.ERB); s != "0.000000000000000001" {
+		t.Fatalf("got %s", s)
+	}
+	if s := unit.FormatUnit(big.NewInt(30000000000), unit.GWei); s != "30" {
+		t.Fatalf("got %s", s)
+	}
+	if s := unit.Format(big.NewInt(-1500000000), unit.GWei); s != "-1.5 gwei" {
+		t.Fatalf("got %s", s)
+	}
+}
+
+func TestWeiTransactions(t *testing.T) {
+	builder := &client.TxBuilder{GasPrice: big.NewInt(1)}
+	value, _ := unit.Parse("0.5 ERB")
+
+	tx, err := builder.NormalTransactionWei(sellerAddress, value, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value().Cmp(value) != 0 {
+		t.Fatalf("value %s, want %s", tx.Value(), value)
+	}
+	tx, err = builder.TokenPledge(nil, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Value().Cmp(unit.ToWei(2, unit.ERB)) != 0 {
+		t.Fatalf("value %s, want 2 ERB", tx.Value())
+	}
+	if _, err := builder.TokenRevokesPledgeWei(big.NewInt(-1)); !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, client.ErrValidation)
+	}
+	if _, err := builder.AdditionalPledgeAmountWei(nil); !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, client.ErrValidation)
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"github.com/wormholes-org/wormholes-client/types"
+)
+
+func TestVerifyOrders(t *testing.T) {
+	account, _, _ := tools.PriKeyToAddress(priKey)
+	other := common.HexToAddress(buyerAddress)
+	worm := client.NewClient(priKey, "")
+
+	data, err := worm.SignBuyer("0xde0b6b3a7640000", "0x0000000000000000000000000000000000000002", exchangeAddress, "0x487", sellerAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buyer types.Buyer
+	json.Unmarshal(data, &buyer)
+	if signer, err := buyer.Verify(account); err != nil || signer != account {
+		t.Fatalf("signer %s, err %v", signer.Hex(), err)
+	}
+	if _, err := buyer.Verify(other); !errors.Is(err, types.ErrSignatureMismatch) {
+		t.Fatalf("err = %v, want %v", err, types.ErrSignatureMismatch)
+	}
+	buyer.Amount = "0xde0b6b3a7640001"
+	if _, err := buyer.Verify(account); !errors.Is(err, types.ErrSignatureMismatch) {
+		t.Fatalf("err = %v, want %v", err, types.ErrSignatureMismatch)
+	}
+	buyer.Sig = "0xzz"
+	if _, err := buyer.Verify(account); err == nil {
+		t.Fatal("expected a malformed signature to be rejected")
+	}
+
+	data, _ = worm.SignSeller2("0x38D7EA4C68000", "0xa", "/ipfs/qqqqqqqqqq", "0", exchangeAddress, "0x703")
+	var seller2 types.Seller2
+	json.Unmarshal(data, &seller2)
+	if signer, err := seller2.Verify(common.Address{}); err != nil || signer != account {
+		t.Fatalf("signer %s, err %v", signer.Hex(), err)
+	}
+
+	data, _ = worm.SignExchanger(account.Hex(), other.Hex(), "0x92b")
+	var auth types.ExchangerAuth
+	json.Unmarshal(data, &auth)
+	if _, err := auth.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	auth.ExchangerOwner = other.Hex()
+	if _, err := auth.Verify(); !errors.Is(err, types.ErrSignatureMismatch) {
+		t.Fatalf("err = %v, want %v", err, types.ErrSignatureMismatch)
+	}
+}
+
+func TestVerifyPreflight(t *testing.T) {
+	buyer, err := client.NewClient(buyerPriKey, "").SignBuyer("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x65d", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	worm := client.NewClient(priKey, "")
+	worm.SetVerifyOrders(true)
+
+	// the buyer order is not signed by the recipient
+	_, err = worm.TransactionNFT(buyer, sellerAddress)
+	if !errors.Is(err, types.ErrSignatureMismatch) || !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, types.ErrSignatureMismatch)
+	}
+	// the orders are valid, the transaction fails because there is no node
+	_, err = worm.TransactionNFT(buyer, buyerAddress)
+	if !errors.Is(err, client.ErrTransport) {
+		t.Fatalf("err = %v, want %v", err, client.ErrTransport)
+	}
+}
+
+func TestVerifySeller(t *testing.T) {
+	seller2, _ := client.NewClient(sellerPriKey, "").SignSeller2("0x9", "0xa", "/ipfs/qqqqqqqqqq", "0", exchangeAddress, "0x703")
+	buyer, err := client.NewClient(buyerPriKey, "").SignBuyer("0x10", "", exchangeAddress, "0x703", exchangeAddress1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the seller signature is not recovered by default
+	worm := client.NewClient(priKey, "")
+	_, err = worm.FoundryExchange(buyer, seller2, buyerAddress)
+	if !errors.Is(err, client.ErrTransport) {
+		t.Fatalf("err = %v, want %v", err, client.ErrTransport)
+	}
+	// the seller named by the buyer did not sign the seller order
+	worm.SetVerifyOrders(true)
+	_, err = worm.FoundryExchange(buyer, seller2, buyerAddress)
+	if !errors.Is(err, types.ErrOrderMismatch) || !errors.Is(err, client.ErrValidation) {
+		t.Fatalf("err = %v, want %v", err, types.ErrOrderMismatch)
+	}
+}
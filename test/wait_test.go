@@ -0,0 +1,127 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wormholes-org/wormholes-client/client"
+)
+
+func TestWaitMined(t *testing.T) {
+	node := newNode(t)
+	node.SetAutoMine(false)
+	worm := client.NewClient(priKey, node.URL())
+	hash, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(20 * time.Millisecond)
+			node.Mine()
+		}
+	}()
+	opts := client.WaitOptions{Confirmations: 2, Timeout: 5 * time.Second, PollInterval: 10 * time.Millisecond}
+	result, err := worm.WaitMined(context.Background(), hash, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Hash != common.HexToHash(hash) || !result.Successful() {
+		t.Fatalf("result %+v", result)
+	}
+	if result.BlockNumber != 1 || result.Confirmations < 2 {
+		t.Fatalf("mined in block %d with %d confirmations, want block 1 with 2", result.BlockNumber, result.Confirmations)
+	}
+}
+
+func TestWaitMinedTimeout(t *testing.T) {
+	node := newNode(t)
+	node.SetAutoMine(false)
+	worm := client.NewClient(priKey, node.URL())
+
+	result, err := worm.SendAndWait(context.Background(), client.WaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 10 * time.Millisecond}, func() (string, error) {
+		return worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("result %+v, err = %v, want %v", result, err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitMinedNewHeads(t *testing.T) {
+	node := newNode(t)
+	node.SetAutoMine(false)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	hash, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for i := 0; i < 4; i++ {
+			time.Sleep(20 * time.Millisecond)
+			node.Mine()
+		}
+	}()
+	// the receipt is never polled, the new heads wake the wait up
+	opts := client.WaitOptions{Confirmations: 3, Timeout: 5 * time.Second, PollInterval: time.Hour}
+	result, err := worm.WaitMined(context.Background(), hash, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BlockNumber != 1 || result.Confirmations != 3 {
+		t.Fatalf("mined in block %d with %d confirmations, want block 1 with 3", result.BlockNumber, result.Confirmations)
+	}
+}
+
+func TestSendAndWaitFailed(t *testing.T) {
+	node, _ := newStateNode(t)
+	worm := client.NewClient(sellerPriKey, node.URL())
+
+	// a reverted transaction is not an error
+	result, err := worm.SendAndWait(context.Background(), client.WaitOptions{Timeout: 5 * time.Second}, func() (string, error) {
+		return worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Successful() || result.Status != types.ReceiptStatusFailed || result.Receipt == nil {
+		t.Fatalf("result %+v, want a failed transaction", result)
+	}
+}
+
+func TestWaitMinedConnectionLost(t *testing.T) {
+	node := newNode(t)
+	node.SetAutoMine(false)
+	worm := client.NewClient(priKey, node.WebsocketURL())
+	defer worm.CloseConnect()
+	hash, err := worm.SNFTToERB("0x8000000000000000000000000000000000000004")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := worm.WaitMined(context.Background(), hash, client.WaitOptions{Timeout: 5 * time.Second, PollInterval: 20 * time.Millisecond})
+		done <- err
+	}()
+	for node.Subscriptions() != 1 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// the transaction is mined while the subscription is lost, the polling finds it
+	node.DropConnections()
+	node.Mine()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the transaction mined while the connection was lost is not found")
+	}
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/client"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"github.com/wormholes-org/wormholes-client/wallet"
+)
+
+func TestKeyStore(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := ioutil.WriteFile(passwordFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	ks := wallet.NewLightKeyStore(dir)
+
+	imported, err := ks.ImportHexKey(sellerPriKey, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != common.HexToAddress(sellerAddress) {
+		t.Fatalf("imported %s, want %s", imported, sellerAddress)
+	}
+	created, err := ks.NewAccount("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts := ks.Accounts(); len(accounts) != 2 {
+		t.Fatalf("keystore has %d accounts, want 2", len(accounts))
+	}
+
+	if _, err := ks.UnlockWithPassword(created, "wrong"); err == nil {
+		t.Fatal("expected unlock with a wrong password to fail")
+	}
+
+	worm, err := ks.NewClient(imported, passwordFile, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seller1, err := worm.SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x677")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := client.NewClient(sellerPriKey, "").SignSeller1("0x38D7EA4C68000", "0x0000000000000000000000000000000000000003", exchangeAddress, "0x677")
+	if !bytes.Equal(seller1, want) {
+		t.Fatalf("seller1 %s, want %s", seller1, want)
+	}
+}
+
+func TestGetPriKey(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	ioutil.WriteFile(passwordFile, []byte("secret\n"), 0600)
+	if _, err := wallet.NewLightKeyStore(dir).ImportHexKey(buyerPriKey, "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := tools.GetPriKey(dir, passwordFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimPrefix(key, "0x") != buyerPriKey {
+		t.Fatalf("key %s, want %s", key, buyerPriKey)
+	}
+
+	ioutil.WriteFile(passwordFile, []byte("wrong\n"), 0600)
+	if _, err := tools.GetPriKey(dir, passwordFile); err == nil {
+		t.Fatal("expected a wrong password to fail")
+	}
+	if _, err := tools.GetPriKey(t.TempDir(), passwordFile); err == nil {
+		t.Fatal("expected an empty keystore to fail")
+	}
+}
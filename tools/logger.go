@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// Logger receives the diagnostics of the client as a message and alternating
+// key value pairs, in the style of log/slog. A *slog.Logger can be used as is.
+type Logger interface {
+	Debug(msg string, keyvals ...interface{})
+	Info(msg string, keyvals ...interface{})
+	Warn(msg string, keyvals ...interface{})
+	Error(msg string, keyvals ...interface{})
+}
+
+// Level is the severity of a log record, the values are the ones of log/slog
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (l Level) String() string {
+	switch {
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	}
+	return "ERROR"
+}
+
+// NopLogger discards all records, it is the default logger
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, keyvals ...interface{}) {}
+func (NopLogger) Info(msg string, keyvals ...interface{})  {}
+func (NopLogger) Warn(msg string, keyvals ...interface{})  {}
+func (NopLogger) Error(msg string, keyvals ...interface{}) {}
+
+// StdLogger writes the records of level Level and above to a standard library
+// logger as key=value text, like the text handler of log/slog
+type StdLogger struct {
+	Out   *log.Logger
+	Level Level
+}
+
+// NewStdLogger returns a StdLogger writing the records of level and above to out,
+// a nil out writes to the standard logger
+func NewStdLogger(out *log.Logger, level Level) *StdLogger {
+	if out == nil {
+		out = log.Default()
+	}
+	return &StdLogger{Out: out, Level: level}
+}
+
+func (l *StdLogger) Debug(msg string, keyvals ...interface{}) { l.log(LevelDebug, msg, keyvals) }
+func (l *StdLogger) Info(msg string, keyvals ...interface{})  { l.log(LevelInfo, msg, keyvals) }
+func (l *StdLogger) Warn(msg string, keyvals ...interface{})  { l.log(LevelWarn, msg, keyvals) }
+func (l *StdLogger) Error(msg string, keyvals ...interface{}) { l.log(LevelError, msg, keyvals) }
+
+func (l *StdLogger) log(level Level, msg string, keyvals []interface{}) {
+	if level < l.Level {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "level=%s msg=%s", level, quote(msg))
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if i+1 == len(keyvals) {
+			fmt.Fprintf(&b, " !BADKEY=%s", quote(key))
+			break
+		}
+		fmt.Fprintf(&b, " %s=%s", key, quote(fmt.Sprint(keyvals[i+1])))
+	}
+	l.Out.Print(b.String())
+}
+
+// quote quotes the values which contain spaces or quotes
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = NopLogger{}
+)
+
+// SetLogger sets the logger of the diagnostics of the tools package, nil discards them.
+// These diagnostics, such as the signatures recovered from the orders, are shared by
+// all the clients, the logger of a client is set with its own SetLogger and does not
+// receive them. SetLogger can be called concurrently with the tools functions.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NopLogger{}
+	}
+	loggerMu.Lock()
+	logger = l
+	loggerMu.Unlock()
+}
+
+// getLogger returns the logger of the diagnostics of the tools package
+func getLogger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return logger
+}
@@ -14,6 +14,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -32,17 +33,35 @@ func GeneratePriKeyHex(no int) []string {
 	return priKeys
 }
 
+// GetPriKey decrypts the first key file of the keystore directory priPath with
+// the password stored in pwdPath and returns the hex encoded private key
 func GetPriKey(priPath, pwdPath string) (string, error) {
 	files, err := ioutil.ReadDir(priPath)
 	if err != nil {
 		return "", xerrors.Errorf("read %s fail. %v", priPath, err)
 	}
-	keyJson, _ := ioutil.ReadFile(files[1].Name())
+	var keyFile string
+	for _, file := range files {
+		if !file.IsDir() && !strings.HasPrefix(file.Name(), ".") {
+			keyFile = filepath.Join(priPath, file.Name())
+			break
+		}
+	}
+	if keyFile == "" {
+		return "", xerrors.Errorf("no key file in %s", priPath)
+	}
+	keyJson, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", xerrors.Errorf("read %s fail. %v", keyFile, err)
+	}
 	passwd, err := ioutil.ReadFile(pwdPath)
 	if err != nil {
 		return "", xerrors.Errorf("read %s fail. %v", pwdPath, err)
 	}
-	key, _ := keystore.DecryptKey(keyJson, strings.Trim(string(passwd), "\n"))
+	key, err := keystore.DecryptKey(keyJson, strings.Trim(string(passwd), "\n"))
+	if err != nil {
+		return "", xerrors.Errorf("decrypt %s fail. %v", keyFile, err)
+	}
 	privateKey := hexutil.Encode(crypto.FromECDSA(key.PrivateKey))
 	return privateKey, nil
 }
@@ -53,7 +72,10 @@ func RecoverAddress(msg string, sigStr string) (common.Address, error) {
 		!strings.HasPrefix(sigStr, "0X") {
 		return common.Address{}, fmt.Errorf("signature must be started with 0x or 0X")
 	}
-	sigData := hexutil.MustDecode(sigStr)
+	sigData, err := hexutil.Decode(sigStr)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid signature: %v", err)
+	}
 	if len(sigData) != 65 {
 		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
 	}
@@ -62,8 +84,7 @@ func RecoverAddress(msg string, sigStr string) (common.Address, error) {
 	}
 	sigData[64] -= 27
 	hash, _ := hashMsg([]byte(msg))
-	fmt.Println("msg ", msg)
-	fmt.Println("sigdebug hash=", hexutil.Encode(hash))
+	getLogger().Debug("recover address", "hash", hexutil.Encode(hash))
 	rpk, err := crypto.SigToPub(hash, sigData)
 	if err != nil {
 		return common.Address{}, err
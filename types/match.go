@@ -0,0 +1,174 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/xerrors"
+)
+
+var (
+	// ErrOrderMismatch is matched by the errors of orders which can not be traded together
+	ErrOrderMismatch = xerrors.New("orders do not match")
+	// ErrOrderExpired is matched by the errors of orders whose block number has passed
+	ErrOrderExpired = xerrors.New("order expired")
+)
+
+// ParseHexBig parses a hexadecimal number such as the prices and the block numbers of the orders
+func ParseHexBig(value string) (*big.Int, error) {
+	if !strings.HasPrefix(value, "0x") && !strings.HasPrefix(value, "0X") {
+		return nil, fmt.Errorf("%q is not string of 0x", value)
+	}
+	number, ok := new(big.Int).SetString(value[2:], 16)
+	if !ok || number.Sign() < 0 {
+		return nil, fmt.Errorf("invalid hex number %q", value)
+	}
+	return number, nil
+}
+
+// Price returns the price offered by the buyer in wei
+func (b *Buyer) Price() (*big.Int, error) {
+	return parseField("buyer price", b.Amount)
+}
+
+// Price returns the price asked by the seller in wei
+func (s *Seller1) Price() (*big.Int, error) {
+	return parseField("seller1 price", s.Amount)
+}
+
+// Price returns the price asked by the seller in wei
+func (s *Seller2) Price() (*big.Int, error) {
+	return parseField("seller2 price", s.Amount)
+}
+
+func parseField(name, value string) (*big.Int, error) {
+	number, err := ParseHexBig(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return number, nil
+}
+
+// sellerTerms are the terms of the seller order of a trade
+type sellerTerms struct {
+	price                  *big.Int
+	amount, exchanger, nft string
+	message, sig           string
+}
+
+// seller returns the terms of the seller order carried by tx, nil when it carries none
+func seller(tx *Transaction) (*sellerTerms, error) {
+	switch {
+	case tx.Seller1 != nil:
+		price, err := tx.Seller1.Price()
+		if err != nil {
+			return nil, err
+		}
+		return &sellerTerms{price, tx.Seller1.Amount, tx.Seller1.Exchanger, tx.Seller1.NFTAddress, tx.Seller1.Message(), tx.Seller1.Sig}, nil
+	case tx.Seller2 != nil:
+		price, err := tx.Seller2.Price()
+		if err != nil {
+			return nil, err
+		}
+		return &sellerTerms{price, tx.Seller2.Amount, tx.Seller2.Exchanger, "", tx.Seller2.Message(), tx.Seller2.Sig}, nil
+	}
+	return nil, nil
+}
+
+// MatchTerms checks the terms of the orders carried by the wormholes transaction tx, without recovering
+// their signatures: the buyer pays at least the seller price, the orders name the same exchanger and NFT
+// and an exchanger authorization is given by the exchanger of the orders.
+func MatchTerms(tx *Transaction) error {
+	terms, err := seller(tx)
+	if err != nil {
+		return err
+	}
+	if tx.Buyer == nil {
+		return nil
+	}
+	price, err := tx.Buyer.Price()
+	if err != nil {
+		return err
+	}
+	if terms != nil {
+		if price.Cmp(terms.price) < 0 {
+			return fmt.Errorf("%w: the buyer price %s is lower than the seller price %s", ErrOrderMismatch, tx.Buyer.Amount, terms.amount)
+		}
+		if !sameAddress(tx.Buyer.Exchanger, terms.exchanger) {
+			return fmt.Errorf("%w: the buyer exchanger %s is not the seller exchanger %s", ErrOrderMismatch, tx.Buyer.Exchanger, terms.exchanger)
+		}
+		if tx.Seller1 != nil && tx.Buyer.NFTAddress != "" && !sameAddress(tx.Buyer.NFTAddress, terms.nft) {
+			return fmt.Errorf("%w: the buyer nft %s is not the seller nft %s", ErrOrderMismatch, tx.Buyer.NFTAddress, terms.nft)
+		}
+	}
+	if tx.ExchangerAuth != nil && !sameAddress(tx.Buyer.Exchanger, tx.ExchangerAuth.ExchangerOwner) {
+		return fmt.Errorf("%w: the buyer exchanger %s did not give the exchanger authorization of %s", ErrOrderMismatch, tx.Buyer.Exchanger, tx.ExchangerAuth.ExchangerOwner)
+	}
+	return nil
+}
+
+// MatchOrders checks that the orders carried by the wormholes transaction tx can be traded together:
+// their terms match, see MatchTerms, and the seller named by the buyer signed the seller order. When
+// height is not zero, the orders must also still be valid at that block height.
+func MatchOrders(tx *Transaction, height uint64) error {
+	if err := MatchTerms(tx); err != nil {
+		return err
+	}
+	terms, _ := seller(tx)
+	if tx.Buyer != nil && terms != nil && tx.Buyer.Seller != "" {
+		_, err := recoverOrder("seller order", terms.message, terms.sig, common.HexToAddress(tx.Buyer.Seller))
+		if err != nil {
+			return fmt.Errorf("%w: the seller named by the buyer did not sign the seller order: %v", ErrOrderMismatch, err)
+		}
+	}
+
+	if height == 0 {
+		return nil
+	}
+	return CheckExpiry(tx, height)
+}
+
+// CheckExpiry checks that the orders carried by the wormholes transaction tx are still valid at the
+// block height: an order is valid before its block number.
+func CheckExpiry(tx *Transaction, height uint64) error {
+	if tx.Buyer != nil {
+		if err := checkExpiry("buyer", tx.Buyer.BlockNumber, height); err != nil {
+			return err
+		}
+	}
+	if tx.Seller1 != nil {
+		if err := checkExpiry("seller1", tx.Seller1.BlockNumber, height); err != nil {
+			return err
+		}
+	}
+	if tx.Seller2 != nil {
+		if err := checkExpiry("seller2", tx.Seller2.BlockNumber, height); err != nil {
+			return err
+		}
+	}
+	if tx.ExchangerAuth != nil {
+		if err := checkExpiry("exchanger_auth", tx.ExchangerAuth.BlockNumber, height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExpiry checks that the order, valid before the block number blockNumber, is valid at height
+func checkExpiry(order, blockNumber string, height uint64) error {
+	number, err := parseField(order+" block_number", blockNumber)
+	if err != nil {
+		return err
+	}
+	if number.Cmp(new(big.Int).SetUint64(height)) <= 0 {
+		return fmt.Errorf("%w: the %s order is valid before block %d, not at block %d", ErrOrderExpired, order, number, height)
+	}
+	return nil
+}
+
+// sameAddress reports whether two hex addresses are equal, regardless of their case
+func sameAddress(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
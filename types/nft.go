@@ -1,8 +1,12 @@
 package types
 
 import (
-	"github.com/ethereum/go-ethereum/common"
+	"fmt"
 	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wormholes-org/wormholes-client/tools"
+	"golang.org/x/xerrors"
 )
 
 type Account struct {
@@ -85,3 +89,80 @@ type MinerProxy struct {
 }
 
 type MinerProxyList []*MinerProxy
+
+// ErrSignatureMismatch is matched by the errors of orders which are not signed by the party they claim
+var ErrSignatureMismatch = xerrors.New("order signature mismatch")
+
+// SignatureMismatchError is returned when the signer of the order Order is not Want
+type SignatureMismatchError struct {
+	Order  string
+	Want   common.Address
+	Signer common.Address
+}
+
+func (e *SignatureMismatchError) Error() string {
+	return fmt.Sprintf("the %s is signed by %s, not by %s", e.Order, e.Signer.Hex(), e.Want.Hex())
+}
+
+func (e *SignatureMismatchError) Is(target error) bool {
+	return target == ErrSignatureMismatch
+}
+
+// recoverOrder recovers the signer of an order, a zero want accepts any signer
+func recoverOrder(order, msg, sig string, want common.Address) (common.Address, error) {
+	signer, err := tools.RecoverAddress(msg, sig)
+	if err != nil {
+		return common.Address{}, xerrors.Errorf("invalid %s signature: %w", order, err)
+	}
+	if want != (common.Address{}) && signer != want {
+		return signer, &SignatureMismatchError{Order: order, Want: want, Signer: signer}
+	}
+	return signer, nil
+}
+
+// Message returns the message signed by the buyer, as by Wallet.SignBuyer
+func (b *Buyer) Message() string {
+	return b.Amount + b.NFTAddress + b.Exchanger + b.BlockNumber + b.Seller
+}
+
+// Verify recovers the signer of the buyer order and checks that it is buyer,
+// a zero buyer only recovers the signer
+func (b *Buyer) Verify(buyer common.Address) (common.Address, error) {
+	return recoverOrder("buyer", b.Message(), b.Sig, buyer)
+}
+
+// Message returns the message signed by the seller, as by Wallet.SignSeller1
+func (s *Seller1) Message() string {
+	return s.Amount + s.NFTAddress + s.Exchanger + s.BlockNumber
+}
+
+// Verify recovers the signer of the seller1 order and checks that it is seller,
+// a zero seller only recovers the signer
+func (s *Seller1) Verify(seller common.Address) (common.Address, error) {
+	return recoverOrder("seller1", s.Message(), s.Sig, seller)
+}
+
+// Message returns the message signed by the seller, as by Wallet.SignSeller2
+func (s *Seller2) Message() string {
+	return s.Amount + s.Royalty + s.MetaURL + s.ExclusiveFlag + s.Exchanger + s.BlockNumber
+}
+
+// Verify recovers the signer of the seller2 order and checks that it is seller,
+// a zero seller only recovers the signer
+func (s *Seller2) Verify(seller common.Address) (common.Address, error) {
+	return recoverOrder("seller2", s.Message(), s.Sig, seller)
+}
+
+// Message returns the message signed by the exchanger owner, as by Wallet.SignExchanger
+func (a *ExchangerAuth) Message() string {
+	return a.ExchangerOwner + a.To + a.BlockNumber
+}
+
+// Verify recovers the signer of the exchanger authorization and checks that it is
+// the ExchangerOwner it names
+func (a *ExchangerAuth) Verify() (common.Address, error) {
+	if !common.IsHexAddress(a.ExchangerOwner) {
+		return common.Address{}, xerrors.Errorf("invalid exchanger_owner %q", a.ExchangerOwner)
+	}
+	return recoverOrder("exchanger_auth", a.Message(), a.Sig, common.HexToAddress(a.ExchangerOwner))
+}
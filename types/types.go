@@ -1,6 +1,10 @@
 package types
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 const WormHolesVersion = "v0.0.1"
 
@@ -39,6 +43,42 @@ const (
 	AccountDelegate
 )
 
+var typeNames = map[uint8]string{
+	Mint:                               "Mint",
+	Transfer:                           "Transfer",
+	Author:                             "Author",
+	AuthorRevoke:                       "AuthorRevoke",
+	AccountAuthor:                      "AccountAuthor",
+	AccountAuthorRevoke:                "AccountAuthorRevoke",
+	SNFTToERB:                          "SNFTToERB",
+	SNFTPledge:                         "SNFTPledge",
+	SNFTRevokesPledge:                  "SNFTRevokesPledge",
+	TokenPledge:                        "TokenPledge",
+	TokenRevokesPledge:                 "TokenRevokesPledge",
+	Open:                               "Open",
+	Close:                              "Close",
+	TransactionNFT:                     "TransactionNFT",
+	BuyerInitiatingTransaction:         "BuyerInitiatingTransaction",
+	FoundryTradeBuyer:                  "FoundryTradeBuyer",
+	FoundryExchange:                    "FoundryExchange",
+	NftExchangeMatch:                   "NftExchangeMatch",
+	FoundryExchangeInitiated:           "FoundryExchangeInitiated",
+	FtDoesNotAuthorizeExchanges:        "FtDoesNotAuthorizeExchanges",
+	AdditionalPledgeAmount:             "AdditionalPledgeAmount",
+	RevokesPledgeAmount:                "RevokesPledgeAmount",
+	VoteOfficialNFT:                    "VoteOfficialNFT",
+	VoteOfficialNFTByApprovedExchanger: "VoteOfficialNFTByApprovedExchanger",
+	UnforzenAccount:                    "UnforzenAccount",
+	AccountDelegate:                    "AccountDelegate",
+}
+
+// TypeName returns the name of the wormholes transaction type txType,
+// ok is false for types unknown to this client
+func TypeName(txType uint8) (name string, ok bool) {
+	name, ok = typeNames[txType]
+	return name, ok
+}
+
 // Transaction struct for handling NFT transactions
 type Transaction struct {
 	Type       uint8  `json:"type"`
@@ -102,3 +142,11 @@ type BlockParticipants struct {
 	Address     common.Address `json:"address"`
 	Coefficient uint8          `json:"coefficient"`
 }
+
+// FeeHistory is the fee market history of a range of blocks
+type FeeHistory struct {
+	OldestBlock  *big.Int     // block corresponding to first response value
+	Reward       [][]*big.Int // priority fees at the requested percentiles, per block
+	BaseFee      []*big.Int   // base fee per gas, one more than the number of blocks
+	GasUsedRatio []float64    // gas used ratio of each block
+}
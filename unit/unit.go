@@ -0,0 +1,117 @@
+// Package unit converts amounts of ERB between wei and decimal strings such as "1.25 ERB".
+package unit
+
+import (
+	"math/big"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Unit is a denomination of ERB, its value is the number of decimals of the amount in wei
+type Unit int
+
+const (
+	Wei  Unit = 0
+	GWei Unit = 9
+	ERB  Unit = 18
+)
+
+func (u Unit) String() string {
+	switch u {
+	case Wei:
+		return "wei"
+	case GWei:
+		return "gwei"
+	case ERB:
+		return "ERB"
+	}
+	return "1e" + big.NewInt(int64(u)).String() + " wei"
+}
+
+// Wei returns the amount of wei in one u
+func (u Unit) Wei() *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(u)), nil)
+}
+
+// units are the denominations accepted by Parse
+var units = map[string]Unit{
+	"wei":  Wei,
+	"gwei": GWei,
+	"erb":  ERB,
+}
+
+// ToWei converts an amount of whole u to wei
+func ToWei(value int64, u Unit) *big.Int {
+	return new(big.Int).Mul(big.NewInt(value), u.Wei())
+}
+
+// Parse parses an amount with its denomination, such as "1.25 ERB", "30 gwei" or "1000wei",
+// and returns it in wei. The denomination is not case sensitive.
+func Parse(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for name, u := range units {
+		// "gwei" ends with "wei", so the longer names are tried first
+		if name == "wei" && strings.HasSuffix(lower, "gwei") {
+			continue
+		}
+		if strings.HasSuffix(lower, name) {
+			return ParseUnit(strings.TrimSpace(s[:len(s)-len(name)]), u)
+		}
+	}
+	return nil, xerrors.Errorf("the amount %q has no unit of wei, gwei or ERB", s)
+}
+
+// ParseUnit parses the decimal amount value, given in u, and returns it in wei.
+// Amounts with more decimals than u has wei digits are rejected.
+func ParseUnit(value string, u Unit) (*big.Int, error) {
+	whole, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		whole, fraction = value[:i], value[i+1:]
+	}
+	if whole == "" && fraction == "" {
+		return nil, xerrors.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > int(u) {
+		return nil, xerrors.Errorf("the amount %q has more than %d decimals", value, int(u))
+	}
+	digits := whole + fraction + strings.Repeat("0", int(u)-len(fraction))
+	if strings.ContainsAny(digits, "+-") {
+		return nil, xerrors.Errorf("invalid amount %q", value)
+	}
+	wei, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, xerrors.Errorf("invalid amount %q", value)
+	}
+	return wei, nil
+}
+
+// Format formats an amount of wei in u with its denomination, such as "1.25 ERB"
+func Format(wei *big.Int, u Unit) string {
+	return FormatUnit(wei, u) + " " + u.String()
+}
+
+// FormatUnit formats an amount of wei as a decimal amount of u, without trailing zeros
+func FormatUnit(wei *big.Int, u Unit) string {
+	if wei == nil {
+		wei = new(big.Int)
+	}
+	sign := ""
+	if wei.Sign() < 0 {
+		sign = "-"
+	}
+	digits := new(big.Int).Abs(wei).String()
+	if u == Wei {
+		return sign + digits
+	}
+	if len(digits) <= int(u) {
+		digits = strings.Repeat("0", int(u)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(u)
+	whole, fraction := digits[:point], strings.TrimRight(digits[point:], "0")
+	if fraction == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + fraction
+}
@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+	"github.com/wormholes-org/wormholes-client/client"
+	"golang.org/x/xerrors"
+)
+
+// DefaultBasePath is the BIP-44 path of the first wormholes account, the accounts
+// of an HDWallet differ by the last component: m/44'/60'/0'/0/i
+const DefaultBasePath = "m/44'/60'/0'/0"
+
+// hardenedOffset is the first index of hardened child keys
+const hardenedOffset = 0x80000000
+
+var errInvalidKey = xerrors.New("the derived key is invalid, use another index")
+
+// NewMnemonic generates a BIP-39 mnemonic from bits of entropy,
+// 128 bits give 12 words and 256 bits give 24 words
+func NewMnemonic(bits int) (string, error) {
+	entropy, err := bip39.NewEntropy(bits)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// ValidateMnemonic checks the words and the checksum of a BIP-39 mnemonic
+func ValidateMnemonic(mnemonic string) error {
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return xerrors.Errorf("invalid mnemonic: %w", err)
+	}
+	return nil
+}
+
+// HDWallet derives the keys of many accounts from a single BIP-39 seed, following BIP-32
+type HDWallet struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+// NewHDWallet creates the wallet of the mnemonic protected by the optional passphrase
+func NewHDWallet(mnemonic, passphrase string) (*HDWallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, passphrase)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid mnemonic: %w", err)
+	}
+	return NewHDWalletFromSeed(seed)
+}
+
+// NewHDWalletFromSeed creates the wallet of a BIP-32 seed
+func NewHDWalletFromSeed(seed []byte) (*HDWallet, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key := new(big.Int).SetBytes(sum[:32])
+	if key.Sign() == 0 || key.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, xerrors.New("the seed gives an invalid master key")
+	}
+	return &HDWallet{key: key, chainCode: sum[32:]}, nil
+}
+
+// Derive returns the signer of the key at path, as "m/44'/60'/0'/0/0"
+func (w *HDWallet) Derive(path string) (*client.KeySigner, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key, chainCode := w.key, w.chainCode
+	for _, index := range derivationPath {
+		key, chainCode, err = deriveChild(key, chainCode, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	privateKey, err := crypto.ToECDSA(ser256(key))
+	if err != nil {
+		return nil, err
+	}
+	return client.NewKeySignerFromECDSA(privateKey), nil
+}
+
+// Account returns the signer of the account index, at DefaultBasePath/index
+func (w *HDWallet) Account(index uint32) (*client.KeySigner, error) {
+	return w.Derive(fmt.Sprintf("%s/%d", DefaultBasePath, index))
+}
+
+// NewClient creates a client signing with the account index, rawurl is handled as by client.NewClient
+func (w *HDWallet) NewClient(index uint32, rawurl string) (*client.Wormholes, error) {
+	signer, err := w.Account(index)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClientWithSigner(signer, rawurl)
+}
+
+// deriveChild derives the child private key index of a BIP-32 extended private key
+func deriveChild(key *big.Int, chainCode []byte, index uint32) (*big.Int, []byte, error) {
+	var data []byte
+	if index >= hardenedOffset {
+		// hardened child: 0x00 || ser256(k) || ser32(i)
+		data = append([]byte{0}, ser256(key)...)
+	} else {
+		// normal child: serP(point(k)) || ser32(i)
+		privateKey, err := crypto.ToECDSA(ser256(key))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = crypto.CompressPubkey(&privateKey.PublicKey)
+	}
+	var ser32 [4]byte
+	binary.BigEndian.PutUint32(ser32[:], index)
+	data = append(data, ser32[:]...)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	n := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(n) >= 0 {
+		return nil, nil, errInvalidKey
+	}
+	child := new(big.Int).Add(tweak, key)
+	child.Mod(child, n)
+	if child.Sign() == 0 {
+		return nil, nil, errInvalidKey
+	}
+	return child, sum[32:], nil
+}
+
+// ser256 serializes a key as 32 big endian bytes
+func ser256(key *big.Int) []byte {
+	buf := make([]byte, 32)
+	return key.FillBytes(buf)
+}
@@ -0,0 +1,129 @@
+// Package wallet keeps the keys of wormholes accounts in encrypted keystore files,
+// in the format of go-ethereum, and creates clients signing with them.
+package wallet
+
+import (
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/wormholes-org/wormholes-client/client"
+	"golang.org/x/xerrors"
+)
+
+// KeyStore manages the encrypted key files of a directory
+type KeyStore struct {
+	ks *keystore.KeyStore
+}
+
+// NewKeyStore opens the keystore directory dir, it is created when the first account is added
+func NewKeyStore(dir string) *KeyStore {
+	return &KeyStore{ks: keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)}
+}
+
+// NewLightKeyStore opens the keystore directory dir, new keys are encrypted with
+// the light scrypt parameters, which use less memory and CPU but are less secure
+func NewLightKeyStore(dir string) *KeyStore {
+	return &KeyStore{ks: keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)}
+}
+
+// NewAccount generates a new key and stores it encrypted with password
+func (k *KeyStore) NewAccount(password string) (common.Address, error) {
+	account, err := k.ks.NewAccount(password)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// ImportHexKey stores the hex encoded private key encrypted with password
+func (k *KeyStore) ImportHexKey(priKey, password string) (common.Address, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimPrefix(priKey, "0x"), "0X"))
+	if err != nil {
+		return common.Address{}, err
+	}
+	account, err := k.ks.ImportECDSA(key, password)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return account.Address, nil
+}
+
+// Accounts lists the addresses of the keystore
+func (k *KeyStore) Accounts() []common.Address {
+	list := k.ks.Accounts()
+	addresses := make([]common.Address, len(list))
+	for i, account := range list {
+		addresses[i] = account.Address
+	}
+	return addresses
+}
+
+// Unlock decrypts the key of address with the password stored in the first line of passwordFile
+func (k *KeyStore) Unlock(address common.Address, passwordFile string) (client.Signer, error) {
+	password, err := ReadPassword(passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	return k.UnlockWithPassword(address, password)
+}
+
+// UnlockWithPassword decrypts the key of address with password and returns a signer
+// using it. The key stays inside the keystore.
+func (k *KeyStore) UnlockWithPassword(address common.Address, password string) (client.Signer, error) {
+	account, err := k.ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, xerrors.Errorf("account %s: %w", address.Hex(), err)
+	}
+	if err := k.ks.Unlock(account, password); err != nil {
+		return nil, xerrors.Errorf("unlock %s: %w", address.Hex(), err)
+	}
+	return &keystoreSigner{ks: k.ks, account: account}, nil
+}
+
+// Lock removes the decrypted key of address from memory
+func (k *KeyStore) Lock(address common.Address) error {
+	return k.ks.Lock(address)
+}
+
+// NewClient unlocks the account address with the password of passwordFile and
+// creates a client signing with it, rawurl is handled as by client.NewClient
+func (k *KeyStore) NewClient(address common.Address, passwordFile, rawurl string) (*client.Wormholes, error) {
+	signer, err := k.Unlock(address, passwordFile)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClientWithSigner(signer, rawurl)
+}
+
+// ReadPassword reads the password stored in the first line of file
+func ReadPassword(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", xerrors.Errorf("read %s fail. %w", file, err)
+	}
+	return strings.TrimRight(strings.SplitN(string(data), "\n", 2)[0], "\r"), nil
+}
+
+// keystoreSigner signs with an unlocked keystore account
+type keystoreSigner struct {
+	ks      *keystore.KeyStore
+	account accounts.Account
+}
+
+func (s *keystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *keystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTx(s.account, tx, chainID)
+}
+
+func (s *keystoreSigner) SignHash(hash []byte) ([]byte, error) {
+	return s.ks.SignHash(s.account, hash)
+}